@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/nobletk/json-parser/internal/ast"
+)
+
+// palette holds the escape sequences written around keys and scalars.
+// Its zero value writes no colors.
+type palette struct {
+	reset   string
+	key     string
+	str     string
+	number  string
+	literal string
+}
+
+// ansiColors are the ANSI colors used by --color.
+var ansiColors = palette{
+	reset:   "\x1b[0m",
+	key:     "\x1b[1;34m",
+	str:     "\x1b[32m",
+	number:  "\x1b[36m",
+	literal: "\x1b[35m",
+}
+
+var colorModes = map[string]bool{
+	"auto":   true,
+	"always": true,
+	"never":  true,
+}
+
+// useColor decides whether output to w is colored for the --color mode.
+// With auto, only terminals get colors.
+func useColor(mode string, w io.Writer) bool {
+	switch mode {
+	case "always":
+		return true
+	case "auto":
+		f, ok := w.(*os.File)
+		if !ok {
+			return false
+		}
+		info, err := f.Stat()
+		return err == nil && info.Mode()&os.ModeCharDevice != 0
+	default:
+		return false
+	}
+}
+
+// writePretty pretty-prints e with two space indentation like
+// json.MarshalIndent, wrapping keys and scalars in the colors of c. Keys
+// are written in the order they were parsed and scalars as they were
+// written, so colored and plain output only differ by the colors.
+func (c palette) writePretty(out *bytes.Buffer, e ast.Element, depth int) error {
+	indent := strings.Repeat("  ", depth+1)
+
+	switch e := e.(type) {
+	case *ast.Object:
+		if len(e.Pairs) == 0 {
+			out.WriteString("{}")
+			return nil
+		}
+
+		out.WriteString("{\n")
+		i := 0
+		var err error
+		e.Each(func(key string, value ast.Element) bool {
+			if i > 0 {
+				out.WriteString(",\n")
+			}
+			i++
+
+			out.WriteString(indent + c.key + `"` + key + `"` + c.reset + ": ")
+			err = c.writePretty(out, value, depth+1)
+			return err == nil
+		})
+		if err != nil {
+			return err
+		}
+		out.WriteString("\n" + indent[2:] + "}")
+	case *ast.ArrayLiteral:
+		if len(e.Elements) == 0 {
+			out.WriteString("[]")
+			return nil
+		}
+
+		out.WriteString("[\n")
+		for i, el := range e.Elements {
+			if i > 0 {
+				out.WriteString(",\n")
+			}
+			out.WriteString(indent)
+			if err := c.writePretty(out, el, depth+1); err != nil {
+				return err
+			}
+		}
+		out.WriteString("\n" + indent[2:] + "]")
+	default:
+		text, err := ast.Marshal(e)
+		if err != nil {
+			return err
+		}
+
+		color := c.literal
+		switch e.(type) {
+		case *ast.StringLiteral:
+			color = c.str
+		case *ast.NumberLiteral:
+			color = c.number
+		}
+		out.WriteString(color + string(text) + c.reset)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/nobletk/json-parser/internal/ast"
+	"gopkg.in/yaml.v3"
+)
+
+// inputDecoders decode the formats accepted by --from other than JSON into
+// the values ast.FromInterface understands.
+var inputDecoders = map[string]func(data []byte) (interface{}, error){
+	"yaml": func(data []byte) (interface{}, error) {
+		var v interface{}
+		err := yaml.Unmarshal(data, &v)
+		return v, err
+	},
+	"toml": func(data []byte) (interface{}, error) {
+		var v map[string]interface{}
+		err := toml.Unmarshal(data, &v)
+		return v, err
+	},
+}
+
+// convertInput turns data in the given --from format into indented JSON.
+// Object keys come out sorted.
+func convertInput(format string, data []byte) ([]byte, error) {
+	v, err := inputDecoders[format](data)
+	if err != nil {
+		return nil, err
+	}
+
+	elem, err := ast.FromInterface(normalizeInput(v))
+	if err != nil {
+		return nil, err
+	}
+	return ast.MarshalIndent(elem, "  ")
+}
+
+// normalizeInput rewrites the values decoders produce that have no JSON
+// counterpart: maps with non-string keys, typed slices of tables and
+// timestamps, which become strings.
+func normalizeInput(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, el := range v {
+			v[k] = normalizeInput(el)
+		}
+		return v
+	case map[interface{}]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, el := range v {
+			out[fmt.Sprint(k)] = normalizeInput(el)
+		}
+		return out
+	case []interface{}:
+		for i, el := range v {
+			v[i] = normalizeInput(el)
+		}
+		return v
+	case []map[string]interface{}:
+		out := make([]interface{}, len(v))
+		for i, el := range v {
+			out[i] = normalizeInput(el)
+		}
+		return out
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return v
+	}
+}
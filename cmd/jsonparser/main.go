@@ -2,94 +2,316 @@ package main
 
 import (
 	"bytes"
+	"compress/gzip"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
 	"os"
+	"strings"
 
+	"github.com/nobletk/json-parser/internal/ast"
 	"github.com/nobletk/json-parser/internal/lexer"
 	"github.com/nobletk/json-parser/internal/parser"
+	"github.com/nobletk/json-parser/internal/token"
 	"github.com/nobletk/json-parser/pkg/mylog"
 	"github.com/spf13/pflag"
 )
 
+const (
+	exitOK = iota
+	exitInvalidJSON
+	exitUsage
+	exitIO
+	// exitInternal reports a failure to write out a document that was
+	// parsed successfully.
+	exitInternal
+)
+
+// maxLogValueLen keeps --debug output readable on documents with huge
+// literals.
+const maxLogValueLen = 200
+
 type config struct {
-	debug bool
+	debug       bool
+	stats       bool
+	dump        bool
+	errorFormat string
+	onDuplicate string
+	color       string
+	maxDepth    int
+	ndjson      bool
+	from        string
+}
+
+var duplicateKeyPolicies = map[string]parser.DuplicateKeyPolicy{
+	"error": parser.DuplicateKeysError,
+	"first": parser.DuplicateKeysFirst,
+	"last":  parser.DuplicateKeysLast,
+}
+
+// jsonError is how errors are printed with --error-format json.
+type jsonError struct {
+	Message string `json:"message"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Offset  int    `json:"offset"`
 }
 
 func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
 	var cfg config
 
-	pflag.BoolVarP(&cfg.debug, "debug", "d", false, "debug mode for logs")
-	pflag.Usage = func() {
+	flags := pflag.NewFlagSet("jsonparser", pflag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.BoolVarP(&cfg.debug, "debug", "d", false, "debug mode for logs")
+	flags.BoolVar(&cfg.stats, "stats", false, "print document statistics instead of the JSON")
+	flags.BoolVar(&cfg.dump, "dump", false, "print the parse tree for debugging instead of the JSON")
+	flags.StringVar(&cfg.errorFormat, "error-format", "text", "format of parse errors: text or json")
+	flags.StringVar(&cfg.onDuplicate, "on-duplicate", "error", "how to handle duplicate keys: error, first or last")
+	flags.StringVar(&cfg.color, "color", "auto", "colorize the JSON output: auto, always or never")
+	flags.IntVar(&cfg.maxDepth, "max-depth", parser.DefaultMaxDepth, "maximum nesting depth of objects and arrays, 0 for no limit")
+	flags.BoolVar(&cfg.ndjson, "ndjson", false, "validate newline-delimited JSON, reporting every line")
+	flags.StringVar(&cfg.from, "from", "json", "format of the input, converted to JSON: json, yaml or toml")
+	flags.Usage = func() {
 		var buf bytes.Buffer
 
 		buf.WriteString("Usage:\n")
 		buf.WriteString(" jsonparser [OPTIONS] <FILEPATH>\n")
 		buf.WriteString(" cat <FILEPATH> | jsonparser [OPTIONS]\n")
 
-		fmt.Fprintf(os.Stderr, buf.String())
-		pflag.PrintDefaults()
+		fmt.Fprint(stderr, buf.String())
+		flags.PrintDefaults()
+
+		buf.Reset()
+		buf.WriteString("Exit codes:\n")
+		buf.WriteString(" 0  valid JSON\n")
+		buf.WriteString(" 1  invalid JSON\n")
+		buf.WriteString(" 2  usage error\n")
+		buf.WriteString(" 3  I/O error, e.g. the file could not be read\n")
+		buf.WriteString(" 4  internal error while writing the output\n")
+
+		fmt.Fprint(stderr, buf.String())
+	}
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, pflag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	if len(flags.Args()) > 1 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	if cfg.errorFormat != "text" && cfg.errorFormat != "json" {
+		fmt.Fprintf(stderr, "Invalid --error-format %q, expected text or json\n", cfg.errorFormat)
+		return exitUsage
+	}
+
+	duplicateKeys, ok := duplicateKeyPolicies[cfg.onDuplicate]
+	if !ok {
+		fmt.Fprintf(stderr, "Invalid --on-duplicate %q, expected error, first or last\n", cfg.onDuplicate)
+		return exitUsage
+	}
+
+	if !colorModes[cfg.color] {
+		fmt.Fprintf(stderr, "Invalid --color %q, expected auto, always or never\n", cfg.color)
+		return exitUsage
 	}
-	pflag.Parse()
 
-	if len(pflag.Args()) > 1 {
-		pflag.Usage()
-		os.Exit(2)
+	if _, ok := inputDecoders[cfg.from]; !ok && cfg.from != "json" {
+		fmt.Fprintf(stderr, "Invalid --from %q, expected json, yaml or toml\n", cfg.from)
+		return exitUsage
 	}
 
-	logger := mylog.CreateLogger(cfg.debug)
+	logger := mylog.CreateLogger(cfg.debug, mylog.WithMaxLogValueLen(maxLogValueLen))
 
-	filePath := pflag.Arg(0)
-	data, err := readData(filePath)
+	filePath := flags.Arg(0)
+	data, err := readData(filePath, stdin)
 	if err != nil {
-		log.Fatal(err)
+		fmt.Fprintf(stderr, "Failed to read input: %s\n", err)
+		return exitIO
+	}
+
+	if cfg.from != "json" {
+		data, err = convertInput(cfg.from, data)
+		if err != nil {
+			fmt.Fprintf(stderr, "Invalid %s input: %s\n", strings.ToUpper(cfg.from), err)
+			return exitInvalidJSON
+		}
+	}
+
+	opts := []parser.Option{parser.WithDuplicateKeys(duplicateKeys), parser.WithMaxDepth(cfg.maxDepth)}
+
+	if cfg.ndjson {
+		return runNDJSON(data, opts, stdout, stderr)
 	}
 
 	var out bytes.Buffer
 
-	out.WriteString("Data:\n")
-	out.WriteString(fmt.Sprintf("%s\n\n", string(data)))
+	if !cfg.stats && !cfg.dump {
+		out.WriteString("Data:\n")
+		out.WriteString(fmt.Sprintf("%s\n\n", string(data)))
+	}
 
 	l := lexer.New(logger, string(data))
-	p := parser.New(l)
+	// Positions refer to the input as the lexer reads it, transcoded to
+	// UTF-8 when it is UTF-16.
+	text := l.Slice(0, l.Len())
+	p := parser.New(l, opts...)
 	parsedJSON, jsonErr := p.ParseFile()
+	if jsonErr != nil && cfg.errorFormat == "json" {
+		errJSON, err := json.Marshal(jsonError{
+			Message: strings.TrimSuffix(jsonErr.Msg, "\n"),
+			Line:    jsonErr.Pos.Line,
+			Column:  jsonErr.Pos.Column,
+			Offset:  offsetOf([]byte(text), jsonErr.Pos),
+		})
+		if err != nil {
+			fmt.Fprintf(stderr, "Marshal() Failed. %s\n", err)
+			return exitInternal
+		}
+
+		fmt.Fprintf(stdout, "%s\n", errJSON)
+		return exitInvalidJSON
+	}
 	if jsonErr != nil {
 		out.WriteString("Invalid JSON:\n")
 		out.WriteString(fmt.Sprintf("    %s", jsonErr.Msg))
 		out.WriteString(fmt.Sprintf("    Position(line %d, column %d)\n", jsonErr.Pos.Line,
 			jsonErr.Pos.Column))
+		for _, line := range strings.SplitAfter(jsonErr.Context(text), "\n") {
+			if line != "" {
+				out.WriteString("    " + line)
+			}
+		}
 
-		fmt.Print(out.String())
-		os.Exit(1)
+		fmt.Fprint(stdout, out.String())
+		return exitInvalidJSON
 	}
 
-	validJSON, err := json.MarshalIndent(parsedJSON.ToInterface(), "", "  ")
-	if err != nil {
-		fmt.Printf("MarshalIndent() Failed. %s\n", err)
-		os.Exit(1)
+	if cfg.stats {
+		writeStats(&out, ast.Stats(parsedJSON.Elements[0]), len(data))
+
+		fmt.Fprint(stdout, out.String())
+		return exitOK
+	}
+
+	if cfg.dump {
+		out.WriteString("Parse Tree:\n")
+		out.WriteString(ast.Dump(parsedJSON.Elements[0]))
+
+		fmt.Fprint(stdout, out.String())
+		return exitOK
+	}
+
+	var colors palette
+	if useColor(cfg.color, stdout) {
+		colors = ansiColors
 	}
 
 	out.WriteString("Valid JSON:\n")
-	out.WriteString(fmt.Sprintf("%s\n", string(validJSON)))
+	if err := colors.writePretty(&out, parsedJSON.Elements[0], 0); err != nil {
+		fmt.Fprintf(stderr, "Marshal() Failed. %s\n", err)
+		return exitInternal
+	}
+	out.WriteString("\n")
 
-	fmt.Print(out.String())
-	os.Exit(0)
+	fmt.Fprint(stdout, out.String())
+	return exitOK
 }
 
-func readData(filePath string) ([]byte, error) {
-	if filePath == "" {
-		r, err := io.ReadAll(os.Stdin)
-		if err != nil {
-			return nil, err
+// runNDJSON validates every line of data as its own document and reports
+// each result, carrying on past invalid lines.
+func runNDJSON(data []byte, opts []parser.Option, stdout, stderr io.Writer) int {
+	var out bytes.Buffer
+	failed := false
+
+	readErr := parser.ParseNDJSONAll(bytes.NewReader(data), func(_ *ast.JSONFile, line int, jErr *parser.JSONErr) {
+		if jErr == nil {
+			out.WriteString(fmt.Sprintf("line %d: valid\n", line))
+			return
 		}
-		return r, nil
-	} else {
-		r, err := os.ReadFile(filePath)
-		if err != nil {
-			return nil, err
+
+		failed = true
+		out.WriteString(fmt.Sprintf("line %d: invalid: %s (column %d)\n", line,
+			strings.TrimSuffix(jErr.Msg, "\n"), jErr.Pos.Column))
+	}, opts...)
+	if readErr != nil {
+		fmt.Fprint(stderr, readErr.Msg)
+		return exitIO
+	}
+
+	fmt.Fprint(stdout, out.String())
+	if failed {
+		return exitInvalidJSON
+	}
+	return exitOK
+}
+
+func writeStats(out *bytes.Buffer, stats ast.DocStats, size int) {
+	out.WriteString("Stats:\n")
+	out.WriteString(fmt.Sprintf("    Objects:   %d\n", stats.Objects))
+	out.WriteString(fmt.Sprintf("    Arrays:    %d\n", stats.Arrays))
+	out.WriteString(fmt.Sprintf("    Strings:   %d\n", stats.Strings))
+	out.WriteString(fmt.Sprintf("    Numbers:   %d\n", stats.Numbers))
+	out.WriteString(fmt.Sprintf("    Booleans:  %d\n", stats.Booleans))
+	out.WriteString(fmt.Sprintf("    Nulls:     %d\n", stats.Nulls))
+	out.WriteString(fmt.Sprintf("    Keys:      %d\n", stats.Keys))
+	out.WriteString(fmt.Sprintf("    Max Depth: %d\n", stats.MaxDepth))
+	out.WriteString(fmt.Sprintf("    Size:      %d bytes\n", size))
+}
+
+// offsetOf converts a line and column reported by the lexer into a byte
+// offset into data, the text the lexer read.
+func offsetOf(data []byte, pos token.Position) int {
+	offset := 0
+	for line := 1; line < pos.Line; line++ {
+		i := bytes.IndexByte(data[offset:], '\n')
+		if i < 0 {
+			return len(data)
 		}
-		return r, nil
+		offset += i + 1
+	}
+	return min(offset+pos.Column-1, len(data))
+}
+
+// gzipMagic starts every gzip stream.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// readData reads the file at filePath, or stdin when it is empty. Input
+// that is gzip-compressed, going by a ".gz" name or the gzip magic bytes,
+// is decompressed.
+func readData(filePath string, stdin io.Reader) ([]byte, error) {
+	var data []byte
+	var err error
+	if filePath == "" {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(filePath)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasSuffix(filePath, ".gz") && !bytes.HasPrefix(data, gzipMagic) {
+		return data, nil
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decompressing gzip: %w", err)
+	}
+	defer zr.Close()
+
+	data, err = io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("decompressing gzip: %w", err)
 	}
+	return data, nil
 }
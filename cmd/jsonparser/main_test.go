@@ -0,0 +1,492 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunStats(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	code := run([]string{"--stats", "testdata/sample.json"}, strings.NewReader(""), &stdout, &stderr)
+
+	expected := `Stats:
+    Objects:   4
+    Arrays:    2
+    Strings:   3
+    Numbers:   4
+    Booleans:  1
+    Nulls:     1
+    Keys:      10
+    Max Depth: 4
+    Size:      157 bytes
+`
+	assert.Equal(t, 0, code)
+	assert.Equal(t, expected, stdout.String())
+	assert.Empty(t, stderr.String())
+}
+
+func TestRunStatsInvalidJSON(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	code := run([]string{"--stats"}, strings.NewReader(`{"key": }`), &stdout, &stderr)
+
+	assert.Equal(t, 1, code)
+	assert.True(t, strings.HasPrefix(stdout.String(), "Invalid JSON:\n"))
+	assert.NotContains(t, stdout.String(), "Stats:")
+}
+
+func TestRunErrorContext(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	code := run(nil, strings.NewReader("{\n  \"a\": 1,\n  \"b\" 2\n}"), &stdout, &stderr)
+
+	expected := "Invalid JSON:\n" +
+		"    Expected ':' after object key 'b', got 'NUMBER' instead\n" +
+		"    Position(line 3, column 7)\n" +
+		"      \"b\" 2\n" +
+		"          ^\n"
+
+	assert.Equal(t, exitInvalidJSON, code)
+	assert.True(t, strings.HasSuffix(stdout.String(), expected))
+}
+
+func TestRunDump(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	code := run([]string{"--dump"}, strings.NewReader(`{"key": [true, null]}`), &stdout, &stderr)
+
+	expected := `Parse Tree:
+Object
+  Key "key" -> Array
+    [0] Boolean true
+    [1] Null
+`
+	assert.Equal(t, 0, code)
+	assert.Equal(t, expected, stdout.String())
+}
+
+func TestRunExitCodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		stdin    string
+		expected int
+	}{
+		{name: "Valid JSON", args: []string{"testdata/sample.json"}, expected: exitOK},
+		{name: "Invalid JSON", stdin: `{"key": }`, expected: exitInvalidJSON},
+		{name: "Unknown Flag", args: []string{"--unknown"}, expected: exitUsage},
+		{name: "Too Many Arguments", args: []string{"a.json", "b.json"}, expected: exitUsage},
+		{name: "Missing File", args: []string{"testdata/missing.json"}, expected: exitIO},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			code := run(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr)
+			assert.Equal(t, tt.expected, code)
+		})
+	}
+}
+
+func TestRunEmptyInput(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	code := run(nil, strings.NewReader(""), &stdout, &stderr)
+
+	assert.Equal(t, exitInvalidJSON, code)
+	assert.Contains(t, stdout.String(), "Invalid JSON:\n    Unexpected end of input, expected a JSON value\n")
+}
+
+func TestRunHelp(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	code := run([]string{"--help"}, strings.NewReader(""), &stdout, &stderr)
+
+	assert.Equal(t, exitOK, code)
+	assert.Empty(t, stdout.String())
+	assert.Contains(t, stderr.String(), "Usage:\n")
+	assert.Contains(t, stderr.String(), "--debug")
+}
+
+func TestRunMissingFileMessage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	code := run([]string{"testdata/missing.json"}, strings.NewReader(""), &stdout, &stderr)
+
+	assert.Equal(t, exitIO, code)
+	assert.Empty(t, stdout.String())
+	assert.Contains(t, stderr.String(), "Failed to read input:")
+	assert.Contains(t, stderr.String(), "missing.json")
+}
+
+func TestRunErrorFormatJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected map[string]interface{}
+	}{
+		{
+			name:  "Single Line",
+			input: `{"key": }`,
+			expected: map[string]interface{}{
+				"message": "Missing value for key 'key'",
+				"line":    float64(1),
+				"column":  float64(9),
+				"offset":  float64(8),
+			},
+		},
+		{
+			name:  "Multiple Lines",
+			input: "{\n  \"a\": 1,\n  \"b\" 2\n}",
+			expected: map[string]interface{}{
+				"message": "Expected ':' after object key 'b', got 'NUMBER' instead",
+				"line":    float64(3),
+				"column":  float64(7),
+				"offset":  float64(18),
+			},
+		},
+		{
+			name:  "UTF-16",
+			input: utf16LE("\n\n{\"a\": }"),
+			expected: map[string]interface{}{
+				"message": "Missing value for key 'a'",
+				"line":    float64(3),
+				"column":  float64(7),
+				"offset":  float64(8),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			code := run([]string{"--error-format", "json"}, strings.NewReader(tt.input), &stdout, &stderr)
+			assert.Equal(t, exitInvalidJSON, code)
+
+			var actual map[string]interface{}
+			require.NoError(t, json.Unmarshal(stdout.Bytes(), &actual), "stdout should only hold the JSON error")
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+// utf16LE encodes the ASCII string s as UTF-16LE with a byte order mark.
+func utf16LE(s string) string {
+	out := []byte{0xff, 0xfe}
+	for i := 0; i < len(s); i++ {
+		out = append(out, s[i], 0)
+	}
+	return string(out)
+}
+
+func TestRunErrorFormatInvalid(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	code := run([]string{"--error-format", "xml"}, strings.NewReader("{}"), &stdout, &stderr)
+
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr.String(), `Invalid --error-format "xml"`)
+}
+
+func TestRunOnDuplicate(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected int
+		contains string
+	}{
+		{name: "Default Error", expected: exitInvalidJSON, contains: "Duplicate JSON property"},
+		{name: "Error", args: []string{"--on-duplicate", "error"}, expected: exitInvalidJSON, contains: "Duplicate JSON property"},
+		{name: "First", args: []string{"--on-duplicate", "first"}, expected: exitOK, contains: "\"a\": 1\n"},
+		{name: "Last", args: []string{"--on-duplicate", "last"}, expected: exitOK, contains: "\"a\": 2\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			code := run(tt.args, strings.NewReader(`{"a":1,"a":2}`), &stdout, &stderr)
+			assert.Equal(t, tt.expected, code)
+			assert.Contains(t, stdout.String(), tt.contains)
+		})
+	}
+}
+
+func TestRunOnDuplicateInvalid(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	code := run([]string{"--on-duplicate", "merge"}, strings.NewReader(`{}`), &stdout, &stderr)
+
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr.String(), `Invalid --on-duplicate "merge"`)
+}
+
+func TestRunMaxDepth(t *testing.T) {
+	nested := strings.Repeat("[", 5) + strings.Repeat("]", 5)
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected int
+		contains string
+	}{
+		{name: "Default Limit", expected: exitOK, contains: "Valid JSON:\n"},
+		{name: "Deep Enough", args: []string{"--max-depth", "5"}, expected: exitOK, contains: "Valid JSON:\n"},
+		{
+			name:     "Too Deep",
+			args:     []string{"--max-depth", "3"},
+			expected: exitInvalidJSON,
+			contains: "Maximum nesting depth of 3 exceeded\n    Position(line 1, column 4)\n",
+		},
+		{name: "No Limit", args: []string{"--max-depth", "0"}, expected: exitOK, contains: "Valid JSON:\n"},
+		{name: "Not A Number", args: []string{"--max-depth", "deep"}, expected: exitUsage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			code := run(tt.args, strings.NewReader(nested), &stdout, &stderr)
+			assert.Equal(t, tt.expected, code)
+			assert.Contains(t, stdout.String(), tt.contains)
+		})
+	}
+}
+
+func TestRunColor(t *testing.T) {
+	input := `{"key": "value", "n": [1, true, null], "empty": {}}`
+
+	tests := []struct {
+		name    string
+		args    []string
+		colored bool
+	}{
+		{name: "Default Auto Without Terminal", colored: false},
+		{name: "Auto Without Terminal", args: []string{"--color", "auto"}, colored: false},
+		{name: "Always", args: []string{"--color", "always"}, colored: true},
+		{name: "Never", args: []string{"--color", "never"}, colored: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			code := run(tt.args, strings.NewReader(input), &stdout, &stderr)
+
+			assert.Equal(t, exitOK, code)
+			assert.Equal(t, tt.colored, strings.Contains(stdout.String(), "\x1b["))
+		})
+	}
+}
+
+func TestRunColorAlways(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	code := run([]string{"--color", "always"}, strings.NewReader(`{"key": ["s", 1.5, false, null, {}]}`),
+		&stdout, &stderr)
+
+	expected := "Valid JSON:\n" +
+		"{\n" +
+		"  \x1b[1;34m\"key\"\x1b[0m: [\n" +
+		"    \x1b[32m\"s\"\x1b[0m,\n" +
+		"    \x1b[36m1.5\x1b[0m,\n" +
+		"    \x1b[35mfalse\x1b[0m,\n" +
+		"    \x1b[35mnull\x1b[0m,\n" +
+		"    {}\n" +
+		"  ]\n" +
+		"}\n"
+
+	assert.Equal(t, exitOK, code)
+	assert.True(t, strings.HasSuffix(stdout.String(), expected))
+}
+
+func TestRunColorMatchesPlainOutput(t *testing.T) {
+	input := `{"b": 1.50, "a": [1e2, "x\u00e9"]}`
+
+	var colored, plain, stderr bytes.Buffer
+	require.Equal(t, exitOK, run([]string{"--color", "always"}, strings.NewReader(input), &colored, &stderr))
+	require.Equal(t, exitOK, run([]string{"--color", "never"}, strings.NewReader(input), &plain, &stderr))
+
+	expected := "Valid JSON:\n" +
+		"{\n" +
+		"  \"b\": 1.50,\n" +
+		"  \"a\": [\n" +
+		"    1e2,\n" +
+		"    \"x\\u00e9\"\n" +
+		"  ]\n" +
+		"}\n"
+
+	assert.True(t, strings.HasSuffix(plain.String(), expected))
+	assert.Equal(t, plain.String(), regexp.MustCompile("\x1b\\[[0-9;]*m").ReplaceAllString(colored.String(), ""))
+}
+
+func TestRunColorInvalid(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	code := run([]string{"--color", "rainbow"}, strings.NewReader(`{}`), &stdout, &stderr)
+
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr.String(), `Invalid --color "rainbow"`)
+}
+
+func TestRunNDJSON(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	input := "{\"id\": 1}\n{\"id\": }\n\n[3]\n[4,]\n"
+	code := run([]string{"--ndjson"}, strings.NewReader(input), &stdout, &stderr)
+
+	expected := "line 1: valid\n" +
+		"line 2: invalid: Missing value for key 'id' (column 8)\n" +
+		"line 4: valid\n" +
+		"line 5: invalid: Trailing commas are not allowed in JSON; remove the ',' before ']' (column 4)\n"
+
+	assert.Equal(t, exitInvalidJSON, code)
+	assert.Equal(t, expected, stdout.String())
+	assert.Empty(t, stderr.String())
+}
+
+func TestRunNDJSONValid(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	code := run([]string{"--ndjson"}, strings.NewReader("[1]\n{\"two\": 2}\n"), &stdout, &stderr)
+
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "line 1: valid\nline 2: valid\n", stdout.String())
+}
+
+func TestRunFromYAML(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	code := run([]string{"--from", "yaml", "testdata/sample.yaml"}, strings.NewReader(""), &stdout, &stderr)
+
+	expected := `Valid JSON:
+{
+  "active": true,
+  "count": 3,
+  "name": "sample",
+  "nested": {
+    "items": [
+      {
+        "id": 1
+      },
+      {
+        "id": 2
+      }
+    ]
+  },
+  "owner": null,
+  "ratio": 0.5,
+  "tags": [
+    "a",
+    "b"
+  ]
+}
+`
+	assert.Equal(t, exitOK, code)
+	assert.True(t, strings.HasSuffix(stdout.String(), expected))
+	assert.Empty(t, stderr.String())
+}
+
+func TestRunFromTOML(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	input := "title = \"x\"\n\n[[items]]\nid = 1\n\n[[items]]\nid = 2\n"
+	code := run([]string{"--from", "toml", "--stats"}, strings.NewReader(input), &stdout, &stderr)
+
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, stdout.String(), "Objects:   3\n")
+	assert.Contains(t, stdout.String(), "Arrays:    1\n")
+}
+
+func TestRunFromInvalid(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		stdin          string
+		expected       int
+		expectedStderr string
+	}{
+		{
+			name:           "Unknown Format",
+			args:           []string{"--from", "xml"},
+			expected:       exitUsage,
+			expectedStderr: "Invalid --from \"xml\", expected json, yaml or toml\n",
+		},
+		{
+			name:           "Invalid YAML",
+			args:           []string{"--from", "yaml"},
+			stdin:          "a: [1, 2",
+			expected:       exitInvalidJSON,
+			expectedStderr: "Invalid YAML input:",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			code := run(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr)
+			assert.Equal(t, tt.expected, code)
+			assert.True(t, strings.HasPrefix(stderr.String(), tt.expectedStderr))
+		})
+	}
+}
+
+func gzipped(t *testing.T, data string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := zw.Write([]byte(data))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestRunGzip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "doc.json.gz")
+	require.NoError(t, os.WriteFile(path, gzipped(t, `{"key": [1, 2]}`), 0o644))
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"--stats", path}, strings.NewReader(""), &stdout, &stderr)
+
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, stdout.String(), "Numbers:   2\n")
+	assert.Contains(t, stdout.String(), "Size:      15 bytes\n")
+	assert.Empty(t, stderr.String())
+}
+
+func TestRunGzipStdin(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	stdin := bytes.NewReader(gzipped(t, `[true]`))
+
+	code := run([]string{"--color", "never"}, stdin, &stdout, &stderr)
+
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "Data:\n[true]\n\nValid JSON:\n[\n  true\n]\n", stdout.String())
+}
+
+func TestRunGzipCorrupt(t *testing.T) {
+	data := gzipped(t, `{"key": "value"}`)
+	path := filepath.Join(t.TempDir(), "doc.json.gz")
+	require.NoError(t, os.WriteFile(path, data[:len(data)-6], 0o644))
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{path}, strings.NewReader(""), &stdout, &stderr)
+
+	assert.Equal(t, exitIO, code)
+	assert.Empty(t, stdout.String())
+	assert.Equal(t, "Failed to read input: decompressing gzip: unexpected EOF\n", stderr.String())
+}
@@ -3,7 +3,12 @@ package ast
 import (
 	"bytes"
 	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf16"
 
 	"github.com/nobletk/json-parser/internal/token"
 )
@@ -19,6 +24,68 @@ type Element interface {
 	elementNode()
 }
 
+// TypeName returns the JSON type of e as used in messages: "object",
+// "array", "string", "number", "boolean" or "null". Frozen objects and
+// arrays have the names of the types they wrap. It returns an empty string
+// for nil.
+func TypeName(e Element) string {
+	switch e.(type) {
+	case *Object, *FrozenObject:
+		return "object"
+	case *ArrayLiteral, *FrozenArray:
+		return "array"
+	case *StringLiteral:
+		return "string"
+	case *NumberLiteral:
+		return "number"
+	case *Boolean:
+		return "boolean"
+	case *Null:
+		return "null"
+	default:
+		return ""
+	}
+}
+
+// Comments holds the comments attached to a node when the lexer is
+// configured to emit them.
+type Comments struct {
+	LeadingComments  []string
+	TrailingComments []string
+}
+
+func (c *Comments) comments() *Comments { return c }
+
+type commented interface {
+	comments() *Comments
+}
+
+func AddLeadingComments(e Element, comments ...string) {
+	if c, ok := e.(commented); ok && len(comments) > 0 {
+		c.comments().LeadingComments = append(c.comments().LeadingComments, comments...)
+	}
+}
+
+func AddTrailingComments(e Element, comments ...string) {
+	if c, ok := e.(commented); ok && len(comments) > 0 {
+		c.comments().TrailingComments = append(c.comments().TrailingComments, comments...)
+	}
+}
+
+func LeadingComments(e Element) []string {
+	if c, ok := e.(commented); ok {
+		return c.comments().LeadingComments
+	}
+	return nil
+}
+
+func TrailingComments(e Element) []string {
+	if c, ok := e.(commented); ok {
+		return c.comments().TrailingComments
+	}
+	return nil
+}
+
 type JSONFile struct {
 	Elements []Element
 }
@@ -39,22 +106,32 @@ func (jf *JSONFile) String() string {
 	return out.String()
 }
 func (jf *JSONFile) ToInterface() interface{} {
+	if len(jf.Elements) == 0 {
+		return nil
+	}
 	return jf.Elements[0].ToInterface()
 }
 
 type Object struct {
+	Comments
+	Links
+	Trivia
 	Token token.Token
 	Pairs map[Element]Element
+	Keys  []Element
 }
 
 func (o *Object) elementNode()         {}
 func (o *Object) TokenLiteral() string { return o.Token.Literal }
+
+// String lists the pairs in parsed order, or sorted by key when the order
+// is unknown, so the output is the same on every call.
 func (o *Object) String() string {
 	var out bytes.Buffer
 
 	pairs := []string{}
-	for key, value := range o.Pairs {
-		pairs = append(pairs, key.String()+":"+value.String())
+	for _, key := range o.orderedKeys() {
+		pairs = append(pairs, key.String()+":"+o.Pairs[key].String())
 	}
 
 	out.WriteString("{")
@@ -63,6 +140,31 @@ func (o *Object) String() string {
 
 	return out.String()
 }
+func (o *Object) orderedKeys() []Element {
+	if len(o.Keys) == len(o.Pairs) {
+		return o.Keys
+	}
+
+	keys := make([]Element, 0, len(o.Pairs))
+	for k := range o.Pairs {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].String() < keys[j].String()
+	})
+	return keys
+}
+
+// Each calls fn for every pair in the order the keys were parsed, without
+// building a map like ToInterface does. Returning false from fn stops the
+// iteration.
+func (o *Object) Each(fn func(key string, value Element) bool) {
+	for _, k := range o.orderedKeys() {
+		if !fn(keyString(k), o.Pairs[k]) {
+			return
+		}
+	}
+}
 func (o *Object) ToInterface() interface{} {
 	out := make(map[string]interface{})
 	for k, v := range o.Pairs {
@@ -76,6 +178,9 @@ func (o *Object) ToInterface() interface{} {
 }
 
 type ArrayLiteral struct {
+	Comments
+	Links
+	Trivia
 	Token    token.Token
 	Elements []Element
 }
@@ -105,8 +210,15 @@ func (al *ArrayLiteral) ToInterface() interface{} {
 }
 
 type StringLiteral struct {
+	Comments
+	Links
+	Trivia
 	Token token.Token
 	Value string
+	// ExtendedEscapes allows the non-standard \xHH and \0 escapes in Value.
+	ExtendedEscapes bool
+	// BraceUnicode allows the ECMAScript \u{XXXX} escape in Value.
+	BraceUnicode bool
 }
 
 func (sl *StringLiteral) elementNode()         {}
@@ -116,7 +228,173 @@ func (sl *StringLiteral) String() string {
 }
 func (sl *StringLiteral) ToInterface() interface{} { return sl.Value }
 
+// Unescaped returns Value with its escape sequences resolved. Surrogate
+// pairs written as two \u escapes are combined into a single rune.
+func (sl *StringLiteral) Unescaped() (string, error) {
+	return unescape(sl.Value, sl.ExtendedEscapes, sl.BraceUnicode)
+}
+
+func unescape(str string, extended, braceUnicode bool) (string, error) {
+	if !strings.Contains(str, "\\") {
+		return str, nil
+	}
+
+	var out strings.Builder
+	for i := 0; i < len(str); i++ {
+		if str[i] != '\\' {
+			out.WriteByte(str[i])
+			continue
+		}
+
+		if i+1 >= len(str) {
+			return "", fmt.Errorf("invalid escape sequence at offset %d", i)
+		}
+
+		switch str[i+1] {
+		case '"', '\\', '/':
+			out.WriteByte(str[i+1])
+		case 'b':
+			out.WriteByte('\b')
+		case 'f':
+			out.WriteByte('\f')
+		case 'n':
+			out.WriteByte('\n')
+		case 'r':
+			out.WriteByte('\r')
+		case 't':
+			out.WriteByte('\t')
+		case 'u':
+			if braceUnicode && i+2 < len(str) && str[i+2] == '{' {
+				r, n, err := unescapeBraceUnicode(str, i)
+				if err != nil {
+					return "", err
+				}
+				out.WriteRune(r)
+				i += n - 2
+				break
+			}
+			r, err := unescapeUnicode(str, i)
+			if err != nil {
+				return "", err
+			}
+			if utf16.IsSurrogate(r) {
+				if r >= 0xdc00 {
+					return "", fmt.Errorf("lone low surrogate \\u%04x at offset %d", r, i)
+				}
+
+				low, err := unescapeUnicode(str, i+6)
+				if err != nil || low < 0xdc00 || low > 0xdfff {
+					return "", fmt.Errorf("lone high surrogate \\u%04x at offset %d", r, i)
+				}
+
+				r = utf16.DecodeRune(r, low)
+				i += 6
+			}
+			out.WriteRune(r)
+			i += 4
+		case 'x':
+			if !extended {
+				return "", fmt.Errorf("invalid escape sequence '\\%c' at offset %d", str[i+1], i)
+			}
+			if i+4 > len(str) {
+				return "", fmt.Errorf("invalid hex escape sequence at offset %d", i)
+			}
+			n, err := strconv.ParseUint(str[i+2:i+4], 16, 8)
+			if err != nil {
+				return "", fmt.Errorf("invalid hex escape sequence at offset %d", i)
+			}
+			out.WriteRune(rune(n))
+			i += 2
+		case '0':
+			if !extended {
+				return "", fmt.Errorf("invalid escape sequence '\\%c' at offset %d", str[i+1], i)
+			}
+			out.WriteByte(0)
+		default:
+			return "", fmt.Errorf("invalid escape sequence '\\%c' at offset %d", str[i+1], i)
+		}
+		i++
+	}
+
+	return out.String(), nil
+}
+
+// EscapeString quotes s as a JSON string literal, escaping quotation
+// marks, reverse solidi and control characters. With asciiOnly every
+// non-ASCII character is escaped as well, as with Marshaler.ASCIIOnly.
+func EscapeString(s string, asciiOnly bool) string {
+	str := escapeString(s)
+	if asciiOnly {
+		str = escapeNonASCII(str)
+	}
+	return `"` + str + `"`
+}
+
+// escapeString is the inverse of unescape. It only escapes what JSON
+// requires: quotation marks, reverse solidi and control characters.
+func escapeString(str string) string {
+	var out strings.Builder
+
+	for i := 0; i < len(str); i++ {
+		switch c := str[i]; c {
+		case '"':
+			out.WriteString("\\\"")
+		case '\\':
+			out.WriteString("\\\\")
+		case '\b':
+			out.WriteString("\\b")
+		case '\f':
+			out.WriteString("\\f")
+		case '\n':
+			out.WriteString("\\n")
+		case '\r':
+			out.WriteString("\\r")
+		case '\t':
+			out.WriteString("\\t")
+		default:
+			if c < 0x20 {
+				fmt.Fprintf(&out, "\\u%04x", c)
+				continue
+			}
+			out.WriteByte(c)
+		}
+	}
+
+	return out.String()
+}
+
+// unescapeUnicode decodes the \uXXXX escape starting at str[i].
+func unescapeUnicode(str string, i int) (rune, error) {
+	if i+6 > len(str) || str[i] != '\\' || str[i+1] != 'u' {
+		return 0, fmt.Errorf("invalid unicode escape sequence at offset %d", i)
+	}
+
+	n, err := strconv.ParseUint(str[i+2:i+6], 16, 16)
+	if err != nil {
+		return 0, fmt.Errorf("invalid unicode escape sequence at offset %d", i)
+	}
+	return rune(n), nil
+}
+
+// unescapeBraceUnicode decodes the \u{X...} escape of 1 to 6 hex digits
+// starting at str[i] and returns its length.
+func unescapeBraceUnicode(str string, i int) (rune, int, error) {
+	end := strings.IndexByte(str[i:], '}')
+	if end < 4 || end > 9 {
+		return 0, 0, fmt.Errorf("invalid unicode escape sequence at offset %d", i)
+	}
+
+	n, err := strconv.ParseUint(str[i+3:i+end], 16, 32)
+	if err != nil || n > unicode.MaxRune {
+		return 0, 0, fmt.Errorf("invalid unicode escape sequence at offset %d", i)
+	}
+	return rune(n), end + 1, nil
+}
+
 type Boolean struct {
+	Comments
+	Links
+	Trivia
 	Token token.Token
 	Value bool
 }
@@ -127,6 +405,9 @@ func (b *Boolean) String() string           { return b.Token.Literal }
 func (b *Boolean) ToInterface() interface{} { return b.Value }
 
 type Null struct {
+	Comments
+	Links
+	Trivia
 	Token token.Token
 	Value string
 }
@@ -137,14 +418,116 @@ func (n *Null) String() string           { return n.Token.Literal }
 func (n *Null) ToInterface() interface{} { return nil }
 
 type NumberLiteral struct {
+	Comments
+	Links
+	Trivia
 	Token token.Token
 	Value float64
+	// Big holds the exact value of the literal when the parser is created
+	// with WithBigNumbers. Value is then only the nearest float64.
+	Big *big.Rat
+	// DetectIntegers makes ToInterface return an int64 for literals written
+	// without a fraction or exponent that fit in one.
+	DetectIntegers bool
+}
+
+func (nl *NumberLiteral) elementNode()         {}
+func (nl *NumberLiteral) TokenLiteral() string { return nl.Token.Literal }
+func (nl *NumberLiteral) String() string       { return nl.Token.Literal }
+func (nl *NumberLiteral) ToInterface() interface{} {
+	if nl.DetectIntegers && (nl.IsHex() || !strings.ContainsAny(nl.Token.Literal, ".eE")) {
+		if n, ok := nl.Int64(); ok {
+			return n
+		}
+	}
+	if nl.Big != nil {
+		return nl.Big
+	}
+	return nl.Value
+}
+
+// IsHex reports whether the original literal is a JSON5 hexadecimal
+// integer such as "0x1F" or "-0x10".
+func (nl *NumberLiteral) IsHex() bool {
+	literal := strings.TrimPrefix(nl.Token.Literal, "-")
+	return strings.HasPrefix(literal, "0x") || strings.HasPrefix(literal, "0X")
 }
 
-func (nl *NumberLiteral) elementNode()             {}
-func (nl *NumberLiteral) TokenLiteral() string     { return nl.Token.Literal }
-func (nl *NumberLiteral) String() string           { return nl.Token.Literal }
-func (nl *NumberLiteral) ToInterface() interface{} { return nl.Value }
+// IsInteger reports whether the original literal denotes an integral value,
+// e.g. "42", "42.0" and "1e3" do while "1.5" and "1e-3" do not.
+func (nl *NumberLiteral) IsInteger() bool {
+	if nl.IsHex() {
+		return true
+	}
+	_, digits, point, ok := splitNumber(nl.Token.Literal)
+	if !ok {
+		return false
+	}
+	return len(digits) == 0 || point >= len(digits)
+}
+
+// Int64 converts the original literal to an int64 without going through
+// float64. It reports false when the literal is not an integer or does not
+// fit in an int64.
+func (nl *NumberLiteral) Int64() (int64, bool) {
+	if nl.IsHex() {
+		n, err := strconv.ParseInt(nl.Token.Literal, 0, 64)
+		return n, err == nil
+	}
+
+	neg, digits, point, ok := splitNumber(nl.Token.Literal)
+	if !ok {
+		return 0, false
+	}
+	if len(digits) == 0 {
+		return 0, true
+	}
+	if point < len(digits) || point > 19 {
+		return 0, false
+	}
+
+	str := digits + strings.Repeat("0", point-len(digits))
+	if neg {
+		str = "-" + str
+	}
+
+	n, err := strconv.ParseInt(str, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+// splitNumber breaks a JSON number literal into its sign, its significant
+// digits without leading or trailing zeros, and the position of the decimal
+// point relative to those digits.
+func splitNumber(literal string) (bool, string, int, bool) {
+	neg := strings.HasPrefix(literal, "-")
+	literal = strings.TrimPrefix(strings.TrimPrefix(literal, "-"), "+")
+
+	mantissa, exponent := literal, 0
+	if i := strings.IndexAny(literal, "eE"); i >= 0 {
+		exp, err := strconv.Atoi(literal[i+1:])
+		if err != nil {
+			return false, "", 0, false
+		}
+		mantissa, exponent = literal[:i], exp
+	}
+
+	intPart, fracPart, _ := strings.Cut(mantissa, ".")
+	if intPart == "" {
+		return false, "", 0, false
+	}
+
+	digits := intPart + fracPart
+	point := len(intPart) + exponent
+
+	trimmed := strings.TrimLeft(digits, "0")
+	point -= len(digits) - len(trimmed)
+	digits = strings.TrimRight(trimmed, "0")
+
+	return neg, digits, point, true
+}
 
 type CommaLiteral struct {
 	Token token.Token
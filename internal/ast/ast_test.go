@@ -0,0 +1,320 @@
+package ast
+
+import (
+	"testing"
+
+	"github.com/nobletk/json-parser/internal/token"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNumberLiteralInteger(t *testing.T) {
+	tests := []struct {
+		name            string
+		literal         string
+		expectedInteger bool
+		expectedInt64   int64
+		expectedFits    bool
+	}{
+		{
+			name:            "Integer",
+			literal:         "42",
+			expectedInteger: true,
+			expectedInt64:   42,
+			expectedFits:    true,
+		},
+		{
+			name:            "Integer With Zero Fraction",
+			literal:         "42.0",
+			expectedInteger: true,
+			expectedInt64:   42,
+			expectedFits:    true,
+		},
+		{
+			name:            "Integer With Exponent",
+			literal:         "1e3",
+			expectedInteger: true,
+			expectedInt64:   1000,
+			expectedFits:    true,
+		},
+		{
+			name:            "Integer With Fraction And Exponent",
+			literal:         "-1.25E2",
+			expectedInteger: true,
+			expectedInt64:   -125,
+			expectedFits:    true,
+		},
+		{
+			name:            "Zero",
+			literal:         "-0.0",
+			expectedInteger: true,
+			expectedInt64:   0,
+			expectedFits:    true,
+		},
+		{
+			name:            "Fraction",
+			literal:         "1.5",
+			expectedInteger: false,
+		},
+		{
+			name:            "Negative Exponent",
+			literal:         "1e-3",
+			expectedInteger: false,
+		},
+		{
+			name:            "Max Int64",
+			literal:         "9223372036854775807",
+			expectedInteger: true,
+			expectedInt64:   9223372036854775807,
+			expectedFits:    true,
+		},
+		{
+			name:            "Min Int64",
+			literal:         "-9223372036854775808",
+			expectedInteger: true,
+			expectedInt64:   -9223372036854775808,
+			expectedFits:    true,
+		},
+		{
+			name:            "Integer Overflowing Int64",
+			literal:         "9223372036854775808",
+			expectedInteger: true,
+		},
+		{
+			name:            "Large Exponent",
+			literal:         "1e400",
+			expectedInteger: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nl := &NumberLiteral{Token: token.Token{Type: token.NUMBER, Literal: tt.literal}}
+
+			assert.Equal(t, tt.expectedInteger, nl.IsInteger(), "IsInteger() isn't correct")
+
+			n, ok := nl.Int64()
+			assert.Equal(t, tt.expectedFits, ok, "Int64() ok isn't correct")
+			assert.Equal(t, tt.expectedInt64, n, "Int64() value isn't correct")
+		})
+	}
+}
+
+func TestStringLiteralUnescaped(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		extended    bool
+		brace       bool
+		expected    string
+		expectedErr string
+	}{
+		{
+			name:     "No Escapes",
+			value:    "key1",
+			expected: "key1",
+		},
+		{
+			name:     "Escaped Quotation Marks",
+			value:    "\\\"key\\\"",
+			expected: "\"key\"",
+		},
+		{
+			name:     "Escaped Reverse Solidus",
+			value:    "key\\\\",
+			expected: "key\\",
+		},
+		{
+			name:     "Escaped Solidus",
+			value:    "key\\/",
+			expected: "key/",
+		},
+		{
+			name:     "Escaped Backspace",
+			value:    "key\\b",
+			expected: "key\b",
+		},
+		{
+			name:     "Escaped Formfeed",
+			value:    "key\\f",
+			expected: "key\f",
+		},
+		{
+			name:     "Escaped Linefeed",
+			value:    "key\\n",
+			expected: "key\n",
+		},
+		{
+			name:     "Escaped Carriage Return",
+			value:    "key\\r",
+			expected: "key\r",
+		},
+		{
+			name:     "Escaped Horizontal Tab",
+			value:    "key\\t",
+			expected: "key\t",
+		},
+		{
+			name:     "Escaped Unicode Sequence",
+			value:    "key\\u00Fa",
+			expected: "keyú",
+		},
+		{
+			name:     "Escaped Surrogate Pair",
+			value:    "\\ud83d\\ude00!",
+			expected: "😀!",
+		},
+		{
+			name:        "Lone High Surrogate",
+			value:       "\\ud83dkey",
+			expectedErr: "lone high surrogate \\ud83d at offset 0",
+		},
+		{
+			name:        "Lone Low Surrogate",
+			value:       "key\\ude00",
+			expectedErr: "lone low surrogate \\ude00 at offset 3",
+		},
+		{
+			name:        "Invalid Escape Sequence",
+			value:       "key\\x41",
+			expectedErr: "invalid escape sequence '\\x' at offset 3",
+		},
+		{
+			name:        "Invalid Unicode Escape Sequence",
+			value:       "key\\u00F",
+			expectedErr: "invalid unicode escape sequence at offset 3",
+		},
+		{
+			name:     "Extended Hex Escape",
+			value:    "key\\x41",
+			extended: true,
+			expected: "keyA",
+		},
+		{
+			name:     "Extended NUL Escape",
+			value:    "key\\0",
+			extended: true,
+			expected: "key\x00",
+		},
+		{
+			name:        "Strict NUL Escape",
+			value:       "key\\0",
+			expectedErr: "invalid escape sequence '\\0' at offset 3",
+		},
+		{
+			name:        "Invalid Extended Hex Escape",
+			value:       "key\\x4",
+			extended:    true,
+			expectedErr: "invalid hex escape sequence at offset 3",
+		},
+		{
+			name:     "Brace Unicode Escape",
+			value:    "\\u{41}\\u{1F600}",
+			brace:    true,
+			expected: "A😀",
+		},
+		{
+			name:        "Invalid Brace Unicode Escape",
+			value:       "key\\u{}",
+			brace:       true,
+			expectedErr: "invalid unicode escape sequence at offset 3",
+		},
+		{
+			name:        "Strict Brace Unicode Escape",
+			value:       "\\u{41}",
+			expectedErr: "invalid unicode escape sequence at offset 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sl := &StringLiteral{Value: tt.value, ExtendedEscapes: tt.extended, BraceUnicode: tt.brace}
+			actual, err := sl.Unescaped()
+
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestEmptyJSONFileToInterface(t *testing.T) {
+	jf := &JSONFile{}
+
+	assert.Nil(t, jf.ToInterface())
+}
+
+func TestTypeName(t *testing.T) {
+	obj := &Object{Pairs: map[Element]Element{}}
+	arr := &ArrayLiteral{}
+
+	tests := []struct {
+		name     string
+		element  Element
+		expected string
+	}{
+		{name: "Object", element: obj, expected: "object"},
+		{name: "Array", element: arr, expected: "array"},
+		{name: "Frozen Object", element: Freeze(obj), expected: "object"},
+		{name: "Frozen Array", element: Freeze(arr), expected: "array"},
+		{name: "String", element: &StringLiteral{Value: "a"}, expected: "string"},
+		{name: "Number", element: &NumberLiteral{Value: 1}, expected: "number"},
+		{name: "Boolean", element: &Boolean{Value: true}, expected: "boolean"},
+		{name: "Null", element: &Null{Value: "null"}, expected: "null"},
+		{name: "Nil", element: nil, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, TypeName(tt.element))
+		})
+	}
+}
+
+func TestEscapeString(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		expected      string
+		expectedASCII string
+	}{
+		{
+			name:          "Plain",
+			input:         "value",
+			expected:      `"value"`,
+			expectedASCII: `"value"`,
+		},
+		{
+			name:          "Quotes And Backslashes",
+			input:         `say "hi" \ bye`,
+			expected:      `"say \"hi\" \\ bye"`,
+			expectedASCII: `"say \"hi\" \\ bye"`,
+		},
+		{
+			name:          "Control Characters",
+			input:         "tab\tnewline\nnul\x00bell\x07",
+			expected:      `"tab\tnewline\nnul\u0000bell\u0007"`,
+			expectedASCII: `"tab\tnewline\nnul\u0000bell\u0007"`,
+		},
+		{
+			name:          "Multi-byte Characters",
+			input:         "café 😀",
+			expected:      `"café 😀"`,
+			expectedASCII: `"caf\u00e9 \ud83d\ude00"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, EscapeString(tt.input, false))
+			assert.Equal(t, tt.expectedASCII, EscapeString(tt.input, true))
+
+			sl := &StringLiteral{Value: tt.expected[1 : len(tt.expected)-1]}
+			unescaped, err := sl.Unescaped()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.input, unescaped)
+		})
+	}
+}
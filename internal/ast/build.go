@@ -0,0 +1,75 @@
+package ast
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/nobletk/json-parser/internal/token"
+)
+
+// NewObject returns an empty object to be filled with Set, e.g.
+//
+//	ast.NewObject().Set("a", ast.Num(1)).Set("b", ast.Str("x"))
+func NewObject() *Object {
+	return &Object{
+		Token: token.Token{Type: token.LBRACE, Literal: "{"},
+		Pairs: make(map[Element]Element),
+	}
+}
+
+// Set adds the pair key: value to o, replacing the value in place when o
+// already has key, and returns o so calls can be chained.
+func (o *Object) Set(key string, value Element) *Object {
+	if o.Pairs == nil {
+		o.Pairs = make(map[Element]Element)
+	}
+
+	for k := range o.Pairs {
+		if unescapedKey(k) == key {
+			o.Pairs[k] = value
+			return o
+		}
+	}
+
+	k := Str(key)
+	o.Pairs[k] = value
+	o.Keys = append(o.Keys, k)
+	return o
+}
+
+// NewArray returns an array holding elements.
+func NewArray(elements ...Element) *ArrayLiteral {
+	if elements == nil {
+		elements = []Element{}
+	}
+	return &ArrayLiteral{Token: token.Token{Type: token.LBRACKET, Literal: "["}, Elements: elements}
+}
+
+// Str returns a string node holding s, escaped as JSON requires.
+func Str(s string) *StringLiteral {
+	str := escapeString(s)
+	return &StringLiteral{Token: token.Token{Type: token.STRING, Literal: str}, Value: str}
+}
+
+// Num returns a number node for f. It panics when f is NaN or infinite,
+// which JSON cannot represent.
+func Num(f float64) *NumberLiteral {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		panic("ast: Num called with " + strconv.FormatFloat(f, 'g', -1, 64))
+	}
+	return number(strconv.FormatFloat(f, 'g', -1, 64), f)
+}
+
+// Bool returns a boolean node for b.
+func Bool(b bool) *Boolean {
+	if b {
+		return &Boolean{Token: token.Token{Type: token.TRUE, Literal: "true"}, Value: true}
+	}
+	return &Boolean{Token: token.Token{Type: token.FALSE, Literal: "false"}, Value: false}
+}
+
+// NewNull returns a null node. It is the builder's counterpart of Str,
+// Num and Bool; it cannot be called Null since that is the node type.
+func NewNull() *Null {
+	return &Null{Token: token.Token{Type: token.NULL, Literal: "null"}, Value: "null"}
+}
@@ -0,0 +1,53 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/nobletk/json-parser/internal/ast"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilder(t *testing.T) {
+	tests := []struct {
+		name     string
+		built    ast.Element
+		expected string
+	}{
+		{
+			name: "Object",
+			built: ast.NewObject().
+				Set("a", ast.Num(1)).
+				Set("b", ast.Str("x")).
+				Set("c", ast.NewArray(ast.Bool(true), ast.NewNull())),
+			expected: `{"a": 1, "b": "x", "c": [true, null]}`,
+		},
+		{
+			name:     "Empty Array",
+			built:    ast.NewArray(),
+			expected: `[]`,
+		},
+		{
+			name:     "Nested",
+			built:    ast.NewArray(ast.NewObject().Set("n", ast.Num(-2.5)), ast.Bool(false)),
+			expected: `[{"n": -2.5}, false]`,
+		},
+		{
+			name:     "Escaped Key And Value",
+			built:    ast.NewObject().Set("say \"hi\"", ast.Str("line\nbreak")),
+			expected: `{"say \"hi\"": "line\nbreak"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.True(t, ast.Equal(parse(t, tt.expected), tt.built))
+		})
+	}
+}
+
+func TestObjectSetReplaces(t *testing.T) {
+	obj := ast.NewObject().Set("a", ast.Num(1)).Set("b", ast.Num(2)).Set("a", ast.Num(3))
+
+	assert.Equal(t, `{"a":3, "b":2}`, obj.String())
+	assert.True(t, ast.Equal(parse(t, `{"a": 3, "b": 2}`), obj))
+}
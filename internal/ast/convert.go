@@ -0,0 +1,138 @@
+package ast
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+
+	"github.com/nobletk/json-parser/internal/token"
+)
+
+// FromInterface is the inverse of ToInterface. It turns maps, slices,
+// strings, numbers, booleans and nil into the matching nodes. Map keys are
+// sorted since Go maps have no order.
+func FromInterface(v interface{}) (Element, error) {
+	switch v := v.(type) {
+	case nil:
+		return NewNull(), nil
+	case bool:
+		return Bool(v), nil
+	case string:
+		return Str(v), nil
+	case float64:
+		return floatNumber(v, 64)
+	case float32:
+		return floatNumber(float64(v), 32)
+	case int:
+		return intNumber(int64(v)), nil
+	case int8:
+		return intNumber(int64(v)), nil
+	case int16:
+		return intNumber(int64(v)), nil
+	case int32:
+		return intNumber(int64(v)), nil
+	case int64:
+		return intNumber(v), nil
+	case uint:
+		return uintNumber(uint64(v)), nil
+	case uint8:
+		return uintNumber(uint64(v)), nil
+	case uint16:
+		return uintNumber(uint64(v)), nil
+	case uint32:
+		return uintNumber(uint64(v)), nil
+	case uint64:
+		return uintNumber(v), nil
+	case *big.Rat:
+		return ratNumber(v)
+	case []interface{}:
+		arr := &ArrayLiteral{Token: token.Token{Type: token.LBRACKET, Literal: "["}, Elements: []Element{}}
+		for i, el := range v {
+			e, err := FromInterface(el)
+			if err != nil {
+				return nil, fmt.Errorf("index %d: %w", i, err)
+			}
+			arr.Elements = append(arr.Elements, e)
+		}
+		return arr, nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		obj := &Object{
+			Token: token.Token{Type: token.LBRACE, Literal: "{"},
+			Pairs: make(map[Element]Element, len(v)),
+		}
+		for _, k := range keys {
+			value, err := FromInterface(v[k])
+			if err != nil {
+				return nil, fmt.Errorf("key %q: %w", k, err)
+			}
+			key, _ := FromInterface(k)
+			obj.Pairs[key] = value
+			obj.Keys = append(obj.Keys, key)
+		}
+		return obj, nil
+	default:
+		return nil, fmt.Errorf("cannot convert %T to an element", v)
+	}
+}
+
+func number(literal string, value float64) *NumberLiteral {
+	return &NumberLiteral{Token: token.Token{Type: token.NUMBER, Literal: literal}, Value: value}
+}
+
+func floatNumber(f float64, bitSize int) (Element, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, fmt.Errorf("cannot convert %v to a JSON number", f)
+	}
+	// Value is what parsing the literal gives back, so a float32 such as
+	// 0.1 does not carry its binary rounding error along.
+	literal := strconv.FormatFloat(f, 'g', -1, bitSize)
+	value, _ := strconv.ParseFloat(literal, 64)
+	return number(literal, value), nil
+}
+
+func intNumber(n int64) Element {
+	return number(strconv.FormatInt(n, 10), float64(n))
+}
+
+func uintNumber(n uint64) Element {
+	return number(strconv.FormatUint(n, 10), float64(n))
+}
+
+// ratNumber keeps r exact when it has a finite decimal expansion, which is
+// always the case for numbers parsed with parser.WithBigNumbers.
+func ratNumber(r *big.Rat) (Element, error) {
+	// A fraction has a finite decimal expansion when its denominator only
+	// has the factors 2 and 5, and needs as many digits as the larger of
+	// the two powers.
+	denom := new(big.Int).Set(r.Denom())
+	twos, fives := 0, 0
+	for denom.Bit(0) == 0 {
+		denom.Rsh(denom, 1)
+		twos++
+	}
+	five, q, rem := big.NewInt(5), new(big.Int), new(big.Int)
+	for {
+		q.QuoRem(denom, five, rem)
+		if rem.Sign() != 0 {
+			break
+		}
+		denom.Set(q)
+		fives++
+	}
+	if denom.Cmp(big.NewInt(1)) != 0 {
+		return nil, fmt.Errorf("cannot convert %s to a JSON number without losing precision", r.RatString())
+	}
+
+	f, _ := r.Float64()
+	nl := number(r.FloatString(max(twos, fives)), f)
+	nl.Big = new(big.Rat).Set(r)
+	return nl, nil
+}
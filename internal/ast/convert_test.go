@@ -0,0 +1,103 @@
+package ast_test
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/nobletk/json-parser/internal/ast"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromInterface(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    interface{}
+		expected string
+	}{
+		{
+			name: "Nested Structure",
+			input: map[string]interface{}{
+				"name":   "config",
+				"count":  float64(3),
+				"ratio":  0.25,
+				"active": true,
+				"owner":  nil,
+				"tags":   []interface{}{"a", "b", float64(1e21)},
+				"nested": map[string]interface{}{
+					"list":  []interface{}{map[string]interface{}{"id": 1}, []interface{}{}},
+					"empty": map[string]interface{}{},
+				},
+			},
+			expected: `{"active":true,"count":3,"name":"config",` +
+				`"nested":{"empty":{},"list":[{"id":1},[]]},"owner":null,"ratio":0.25,"tags":["a","b",1e+21]}`,
+		},
+		{
+			name:     "Escaped String",
+			input:    "quote \" slash \\ newline \n nul \x00 é",
+			expected: `"quote \" slash \\ newline \n nul \u0000 é"`,
+		},
+		{
+			name:     "Integer Types",
+			input:    []interface{}{int8(-8), uint16(16), int64(math.MaxInt64), uint64(math.MaxUint64), float32(0.1)},
+			expected: `[-8,16,9223372036854775807,18446744073709551615,0.1]`,
+		},
+		{
+			name:     "Big Rationals",
+			input:    []interface{}{big.NewRat(1, 8), big.NewRat(-3, 1), new(big.Rat).SetFrac64(1, 1000)},
+			expected: `[0.125,-3,0.001]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := ast.FromInterface(tt.input)
+			require.NoError(t, err)
+
+			out, err := ast.Marshal(e)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(out))
+
+			reparsed, err := ast.Marshal(parse(t, "["+string(out)+"]"))
+			require.NoError(t, err)
+			assert.Equal(t, "["+tt.expected+"]", string(reparsed), "marshaled output should round-trip")
+		})
+	}
+}
+
+func TestFromInterfaceErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    interface{}
+		expected string
+	}{
+		{
+			name:     "Unsupported Type",
+			input:    map[string]interface{}{"ch": make(chan int)},
+			expected: `key "ch": cannot convert chan int to an element`,
+		},
+		{
+			name:     "NaN",
+			input:    []interface{}{1, math.NaN()},
+			expected: "index 1: cannot convert NaN to a JSON number",
+		},
+		{
+			name:     "Infinity",
+			input:    math.Inf(1),
+			expected: "cannot convert +Inf to a JSON number",
+		},
+		{
+			name:     "Repeating Decimal",
+			input:    big.NewRat(1, 3),
+			expected: "cannot convert 1/3 to a JSON number without losing precision",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ast.FromInterface(tt.input)
+			assert.EqualError(t, err, tt.expected)
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package ast
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Change is a difference found by Diff at the node addressed by Pointer.
+// Old is nil when the node was added and New is nil when it was removed.
+type Change struct {
+	Pointer string
+	Old     Element
+	New     Element
+}
+
+// Diff returns the changes that turn a into b. Objects are compared key by
+// key and arrays index by index, so only the innermost differing nodes are
+// reported. Nodes are compared like Equal does. Changes to an object are
+// listed in the key order of a, followed by keys only found in b.
+func Diff(a, b Element) []Change {
+	changes := []Change{}
+	diff(a, b, "", &changes)
+	return changes
+}
+
+func diff(a, b Element, pointer string, changes *[]Change) {
+	a, b = Thaw(a), Thaw(b)
+
+	switch a := a.(type) {
+	case *Object:
+		if b, ok := b.(*Object); ok {
+			diffObjects(a, b, pointer, changes)
+			return
+		}
+	case *ArrayLiteral:
+		if b, ok := b.(*ArrayLiteral); ok {
+			diffArrays(a, b, pointer, changes)
+			return
+		}
+	}
+
+	if !Equal(a, b) {
+		*changes = append(*changes, Change{Pointer: pointer, Old: a, New: b})
+	}
+}
+
+func diffObjects(a, b *Object, pointer string, changes *[]Change) {
+	values := make(map[string]Element, len(b.Pairs))
+	for k, v := range b.Pairs {
+		values[unescapedKey(k)] = v
+	}
+
+	seen := make(map[string]bool, len(a.Pairs))
+	for _, k := range a.orderedKeys() {
+		key := unescapedKey(k)
+		seen[key] = true
+
+		path := pointer + "/" + pointerEscaper.Replace(key)
+		if other, ok := values[key]; ok {
+			diff(a.Pairs[k], other, path, changes)
+		} else {
+			*changes = append(*changes, Change{Pointer: path, Old: a.Pairs[k]})
+		}
+	}
+
+	for _, k := range b.orderedKeys() {
+		if key := unescapedKey(k); !seen[key] {
+			path := pointer + "/" + pointerEscaper.Replace(key)
+			*changes = append(*changes, Change{Pointer: path, New: b.Pairs[k]})
+		}
+	}
+}
+
+func diffArrays(a, b *ArrayLiteral, pointer string, changes *[]Change) {
+	for i := 0; i < max(len(a.Elements), len(b.Elements)); i++ {
+		path := pointer + "/" + strconv.Itoa(i)
+
+		switch {
+		case i >= len(b.Elements):
+			*changes = append(*changes, Change{Pointer: path, Old: a.Elements[i]})
+		case i >= len(a.Elements):
+			*changes = append(*changes, Change{Pointer: path, New: b.Elements[i]})
+		default:
+			diff(a.Elements[i], b.Elements[i], path, changes)
+		}
+	}
+}
+
+// DiffString formats the changes between a and b for people, e.g. to show
+// why a golden file does not match. Every change is a "-" line with the old
+// value and a "+" line with the new one, each prefixed by its JSON Pointer,
+// e.g. "- /key3/1: 1" followed by "+ /key3/1: 2".
+//
+// A change of the whole document is shown at "(root)", since its pointer
+// is empty. It returns an empty string when a and b are equal.
+func DiffString(a, b Element) string {
+	var out strings.Builder
+
+	for _, c := range Diff(a, b) {
+		path := c.Pointer
+		if path == "" {
+			path = "(root)"
+		}
+
+		if c.Old != nil {
+			out.WriteString("- " + path + ": " + diffValue(c.Old) + "\n")
+		}
+		if c.New != nil {
+			out.WriteString("+ " + path + ": " + diffValue(c.New) + "\n")
+		}
+	}
+
+	return out.String()
+}
+
+func diffValue(e Element) string {
+	m := &Marshaler{NormalizeStrings: true, OmitComments: true}
+	out, err := m.Marshal(e)
+	if err != nil {
+		return e.String()
+	}
+	return string(out)
+}
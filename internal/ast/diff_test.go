@@ -0,0 +1,72 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/nobletk/json-parser/internal/ast"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	a := parse(t, `{"name": "a", "tags": ["x", "y"], "n": {"v": 1, "gone": true}}`)
+	b := parse(t, `{"name": "b", "tags": ["x"], "n": {"v": 1.0, "new": null}}`)
+
+	changes := ast.Diff(a, b)
+
+	pointers := []string{}
+	for _, c := range changes {
+		pointers = append(pointers, c.Pointer)
+	}
+	assert.Equal(t, []string{"/name", "/tags/1", "/n/gone", "/n/new"}, pointers)
+
+	assert.Equal(t, `"a"`, changes[0].Old.String())
+	assert.Equal(t, `"b"`, changes[0].New.String())
+	assert.Nil(t, changes[1].New, "removed element should have no new value")
+	assert.Nil(t, changes[3].Old, "added key should have no old value")
+}
+
+func TestDiffString(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        string
+		b        string
+		expected string
+	}{
+		{
+			name:     "Equal Documents",
+			a:        `{"a": [1, 2], "b": "s"}`,
+			b:        `{"b": "s", "a": [1.0, 2e0]}`,
+			expected: "",
+		},
+		{
+			name: "Changed Nested Values",
+			a:    `{"key3": ["value", 1, true], "key4": {"key5": "old"}}`,
+			b:    `{"key3": ["value", 2, true], "key4": {"key5": {"new": [null]}}}`,
+			expected: "- /key3/1: 1\n" +
+				"+ /key3/1: 2\n" +
+				"- /key4/key5: \"old\"\n" +
+				"+ /key4/key5: {\"new\":[null]}\n",
+		},
+		{
+			name: "Added And Removed",
+			a:    `{"a/b": 1, "list": [1, 2]}`,
+			b:    `{"list": [1, 2, 3], "c~d": false}`,
+			expected: "- /a~1b: 1\n" +
+				"+ /list/2: 3\n" +
+				"+ /c~0d: false\n",
+		},
+		{
+			name: "Different Root Types",
+			a:    `{}`,
+			b:    `[]`,
+			expected: "- (root): {}\n" +
+				"+ (root): []\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ast.DiffString(parse(t, tt.a), parse(t, tt.b)))
+		})
+	}
+}
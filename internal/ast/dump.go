@@ -0,0 +1,64 @@
+package ast
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Dump renders e as an indented tree annotated with node types, e.g.
+//
+//	Object
+//	  Key "a" -> Number 1
+//	  Key "b" -> Array
+//	    [0] Null
+//
+// It is meant for debugging the parser and is not valid JSON.
+func Dump(e Element) string {
+	var out bytes.Buffer
+	dump(&out, "", e, 0)
+	return out.String()
+}
+
+func dump(out *bytes.Buffer, prefix string, e Element, depth int) {
+	out.WriteString(strings.Repeat("  ", depth))
+	out.WriteString(prefix)
+	out.WriteString(dumpNode(e))
+	out.WriteString("\n")
+
+	switch e := unwrap(e).(type) {
+	case *Object:
+		for _, k := range e.orderedKeys() {
+			dump(out, `Key "`+keyString(k)+`" -> `, e.Pairs[k], depth+1)
+		}
+	case *ArrayLiteral:
+		for i, el := range e.Elements {
+			dump(out, fmt.Sprintf("[%d] ", i), el, depth+1)
+		}
+	}
+}
+
+func dumpNode(e Element) string {
+	switch e := e.(type) {
+	case *Object:
+		return "Object"
+	case *ArrayLiteral:
+		return "Array"
+	case *FrozenObject:
+		return "Frozen Object"
+	case *FrozenArray:
+		return "Frozen Array"
+	case *StringLiteral:
+		return `String "` + e.Value + `"`
+	case *NumberLiteral:
+		return "Number " + e.String()
+	case *Boolean:
+		return fmt.Sprintf("Boolean %t", e.Value)
+	case *Null:
+		return "Null"
+	case nil:
+		return "<nil>"
+	default:
+		return fmt.Sprintf("%T", e)
+	}
+}
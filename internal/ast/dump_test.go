@@ -0,0 +1,29 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/nobletk/json-parser/internal/ast"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDump(t *testing.T) {
+	input := `{
+		"b": [1, "two", {}],
+		"a": {"c": true, "d": null},
+		"e": -0.5e2
+	}`
+
+	expected := `Object
+  Key "b" -> Array
+    [0] Number 1
+    [1] String "two"
+    [2] Object
+  Key "a" -> Object
+    Key "c" -> Boolean true
+    Key "d" -> Null
+  Key "e" -> Number -0.5e2
+`
+
+	assert.Equal(t, expected, ast.Dump(parse(t, input)))
+}
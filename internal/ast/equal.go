@@ -0,0 +1,186 @@
+package ast
+
+import (
+	"math/big"
+	"strings"
+)
+
+// Comparer reports whether two documents hold the same data. Object key
+// order, formatting and string escapes are ignored.
+type Comparer struct {
+	// ExactNumbers compares numbers by their literal, so 1, 1.0 and 1e0
+	// differ. By default they are compared by value.
+	ExactNumbers bool
+}
+
+// Equal reports whether a and b hold the same data, comparing numbers by
+// value. It is a shortcut for the Equal method of a zero Comparer.
+func Equal(a, b Element) bool {
+	c := &Comparer{}
+	return c.Equal(a, b)
+}
+
+// Equal reports whether a and b are equal. Frozen elements compare equal
+// to the elements they were frozen from.
+func (c *Comparer) Equal(a, b Element) bool {
+	a, b = unwrap(a), unwrap(b)
+
+	switch a := a.(type) {
+	case *Object:
+		b, ok := b.(*Object)
+		if !ok || len(a.Pairs) != len(b.Pairs) {
+			return false
+		}
+
+		values := make(map[string]Element, len(b.Pairs))
+		for k, v := range b.Pairs {
+			values[unescapedKey(k)] = v
+		}
+		for k, v := range a.Pairs {
+			other, ok := values[unescapedKey(k)]
+			if !ok || !c.Equal(v, other) {
+				return false
+			}
+		}
+		return true
+	case *ArrayLiteral:
+		b, ok := b.(*ArrayLiteral)
+		if !ok || len(a.Elements) != len(b.Elements) {
+			return false
+		}
+
+		for i := range a.Elements {
+			if !c.Equal(a.Elements[i], b.Elements[i]) {
+				return false
+			}
+		}
+		return true
+	case *StringLiteral:
+		b, ok := b.(*StringLiteral)
+		return ok && unescapedKey(a) == unescapedKey(b)
+	case *NumberLiteral:
+		b, ok := b.(*NumberLiteral)
+		return ok && c.equalNumbers(a, b)
+	case *Boolean:
+		b, ok := b.(*Boolean)
+		return ok && a.Value == b.Value
+	case *Null:
+		_, ok := b.(*Null)
+		return ok
+	case nil:
+		return b == nil
+	default:
+		return false
+	}
+}
+
+func (c *Comparer) equalNumbers(a, b *NumberLiteral) bool {
+	if c.ExactNumbers {
+		return a.Token.Literal == b.Token.Literal
+	}
+	// Numbers parsed with parser.WithBigNumbers are compared exactly, since
+	// their float64 values may have been rounded to the same number.
+	if a.Big != nil && b.Big != nil {
+		return a.Big.Cmp(b.Big) == 0
+	}
+	return a.Value == b.Value
+}
+
+// EqualInterface reports whether e holds the same data as v, a value like
+// the ones ToInterface returns, without converting e first. Numbers are
+// compared by value, so the literal 1.0 equals both float64(1) and int(1).
+// Object key order is ignored.
+func EqualInterface(e Element, v interface{}) bool {
+	switch e := unwrap(e).(type) {
+	case *Object:
+		m, ok := v.(map[string]interface{})
+		if !ok || len(e.Pairs) != len(m) {
+			return false
+		}
+
+		for k, value := range e.Pairs {
+			other, ok := m[unescapedKey(k)]
+			if !ok || !EqualInterface(value, other) {
+				return false
+			}
+		}
+		return true
+	case *ArrayLiteral:
+		s, ok := v.([]interface{})
+		if !ok || len(e.Elements) != len(s) {
+			return false
+		}
+
+		for i := range e.Elements {
+			if !EqualInterface(e.Elements[i], s[i]) {
+				return false
+			}
+		}
+		return true
+	case *StringLiteral:
+		s, ok := v.(string)
+		return ok && unescapedKey(e) == s
+	case *NumberLiteral:
+		return equalNumberInterface(e, v)
+	case *Boolean:
+		b, ok := v.(bool)
+		return ok && e.Value == b
+	case *Null:
+		return v == nil
+	default:
+		return false
+	}
+}
+
+func equalNumberInterface(nl *NumberLiteral, v interface{}) bool {
+	switch v := v.(type) {
+	case float64:
+		return nl.Value == v
+	case float32:
+		return nl.Value == float64(v)
+	case int:
+		return equalInt(nl, int64(v))
+	case int8:
+		return equalInt(nl, int64(v))
+	case int16:
+		return equalInt(nl, int64(v))
+	case int32:
+		return equalInt(nl, int64(v))
+	case int64:
+		return equalInt(nl, v)
+	case uint:
+		return equalRat(nl, new(big.Rat).SetUint64(uint64(v)))
+	case uint8:
+		return equalInt(nl, int64(v))
+	case uint16:
+		return equalInt(nl, int64(v))
+	case uint32:
+		return equalInt(nl, int64(v))
+	case uint64:
+		return equalRat(nl, new(big.Rat).SetUint64(v))
+	case *big.Rat:
+		return equalRat(nl, v)
+	default:
+		return false
+	}
+}
+
+// equalInt compares the literal of nl to n exactly, so large integers are
+// not rounded through float64.
+func equalInt(nl *NumberLiteral, n int64) bool {
+	i, ok := nl.Int64()
+	return ok && i == n
+}
+
+func equalRat(nl *NumberLiteral, r *big.Rat) bool {
+	if nl.Big != nil {
+		return nl.Big.Cmp(r) == 0
+	}
+	literal := strings.TrimPrefix(nl.Token.Literal, "+")
+	if nl.IsHex() {
+		n, ok := new(big.Int).SetString(literal, 0)
+		return ok && new(big.Rat).SetInt(n).Cmp(r) == 0
+	}
+	exact, ok := new(big.Rat).SetString(literal)
+	return ok && exact.Cmp(r) == 0
+}
@@ -0,0 +1,179 @@
+package ast_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/nobletk/json-parser/internal/ast"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		name          string
+		a             string
+		b             string
+		numeric       bool
+		exactLiterals bool
+	}{
+		{
+			name:          "Same Integer",
+			a:             `[1]`,
+			b:             `[1]`,
+			numeric:       true,
+			exactLiterals: true,
+		},
+		{
+			name:          "Integer And Decimal",
+			a:             `[1]`,
+			b:             `[1.0]`,
+			numeric:       true,
+			exactLiterals: false,
+		},
+		{
+			name:          "Integer And Exponent",
+			a:             `{"n": 1}`,
+			b:             `{"n": 1e0}`,
+			numeric:       true,
+			exactLiterals: false,
+		},
+		{
+			name:          "Different Numbers",
+			a:             `[1]`,
+			b:             `[2]`,
+			numeric:       false,
+			exactLiterals: false,
+		},
+		{
+			name:          "Reordered Keys",
+			a:             `{"a": 1, "b": [true, null]}`,
+			b:             `{"b": [true, null], "a": 1}`,
+			numeric:       true,
+			exactLiterals: true,
+		},
+		{
+			name:          "Escaped Strings",
+			a:             `{"ab": "x\/y"}`,
+			b:             `{"ab": "x/y"}`,
+			numeric:       true,
+			exactLiterals: true,
+		},
+		{
+			name:          "Array Order Matters",
+			a:             `[1, 2]`,
+			b:             `[2, 1]`,
+			numeric:       false,
+			exactLiterals: false,
+		},
+		{
+			name:          "Missing Key",
+			a:             `{"a": 1, "b": 2}`,
+			b:             `{"a": 1, "c": 2}`,
+			numeric:       false,
+			exactLiterals: false,
+		},
+		{
+			name:          "Different Types",
+			a:             `{"a": "1"}`,
+			b:             `{"a": 1}`,
+			numeric:       false,
+			exactLiterals: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := parse(t, tt.a), parse(t, tt.b)
+
+			assert.Equal(t, tt.numeric, ast.Equal(a, b), "numeric comparison")
+			assert.Equal(t, tt.numeric, ast.Equal(b, a), "numeric comparison, swapped")
+
+			exact := &ast.Comparer{ExactNumbers: true}
+			assert.Equal(t, tt.exactLiterals, exact.Equal(a, b), "exact comparison")
+		})
+	}
+}
+
+func TestEqualFrozen(t *testing.T) {
+	doc := parse(t, `{"a": [1, {"b": null}]}`)
+
+	assert.True(t, ast.Equal(ast.Freeze(doc), doc))
+	assert.True(t, ast.Equal(doc, ast.Freeze(parse(t, `{"a": [1.0, {"b": null}]}`))))
+}
+
+func TestEqualInterface(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		value    interface{}
+		expected bool
+	}{
+		{
+			name:  "Object With Nested Values",
+			input: `{"key1": "value", "key2": -123, "key3": ["value", 1, true, null, -0.2e2]}`,
+			value: map[string]interface{}{
+				"key3": []interface{}{"value", float64(1), true, nil, float64(-20)},
+				"key2": float64(-123),
+				"key1": "value",
+			},
+			expected: true,
+		},
+		{
+			name:     "Integers Compare Numerically",
+			input:    `[1.0, 1e2, 9007199254740993]`,
+			value:    []interface{}{1, int64(100), uint64(9007199254740993)},
+			expected: true,
+		},
+		{
+			name:     "Large Integer Not Rounded",
+			input:    `[9007199254740993]`,
+			value:    []interface{}{int64(9007199254740992)},
+			expected: false,
+		},
+		{
+			name:     "Escaped String",
+			input:    `{"a\"b": "é"}`,
+			value:    map[string]interface{}{`a"b`: "é"},
+			expected: true,
+		},
+		{
+			name:     "Missing Key",
+			input:    `{"a": 1, "b": 2}`,
+			value:    map[string]interface{}{"a": float64(1), "c": float64(2)},
+			expected: false,
+		},
+		{
+			name:     "Different Length",
+			input:    `[1, 2]`,
+			value:    []interface{}{float64(1)},
+			expected: false,
+		},
+		{
+			name:     "Different Types",
+			input:    `{"a": "1"}`,
+			value:    map[string]interface{}{"a": float64(1)},
+			expected: false,
+		},
+		{
+			name:     "Null And Missing Value",
+			input:    `[null, false]`,
+			value:    []interface{}{nil, nil},
+			expected: false,
+		},
+		{
+			name:     "Big Rational",
+			input:    `[0.1]`,
+			value:    []interface{}{big.NewRat(1, 10)},
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := parse(t, tt.input)
+
+			assert.Equal(t, tt.expected, ast.EqualInterface(root, tt.value))
+			assert.Equal(t, tt.expected, ast.EqualInterface(ast.Freeze(root), tt.value), "frozen")
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package ast
+
+// Match is an element found by FindAll together with its JSON Pointer,
+// e.g. "/key3/1".
+type Match struct {
+	Pointer string
+	Element Element
+}
+
+// FindAll returns every node under root, root included, for which match
+// returns true, in the order Walk visits them.
+func FindAll(root Element, match func(Element) bool) []Match {
+	matches := []Match{}
+
+	Walk(root, func(path []string, e Element) bool {
+		if match(e) {
+			matches = append(matches, Match{Pointer: pathPointer(path), Element: e})
+		}
+		return true
+	})
+
+	return matches
+}
+
+// pathPointer turns a path from Walk, which holds keys as written in the
+// document, into a JSON Pointer to the same node.
+func pathPointer(path []string) string {
+	segments := make([]string, len(path))
+	for i, seg := range path {
+		if key, err := unescape(seg, true, true); err == nil {
+			seg = key
+		}
+		segments[i] = seg
+	}
+	return joinPointer(segments)
+}
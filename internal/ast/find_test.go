@@ -0,0 +1,66 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/nobletk/json-parser/internal/ast"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindAll(t *testing.T) {
+	root := parse(t, `{
+		"key1": "value",
+		"key2": -123,
+		"key3": ["value", 1, true, null, -0.2e2],
+		"key4": {"a/b": {"c~d": 0.5}, "k": [[7]]}
+	}`)
+
+	tests := []struct {
+		name     string
+		match    func(ast.Element) bool
+		expected []string
+	}{
+		{
+			name: "Numeric Leaves",
+			match: func(e ast.Element) bool {
+				_, ok := e.(*ast.NumberLiteral)
+				return ok
+			},
+			expected: []string{"/key2", "/key3/1", "/key3/4", "/key4/a~1b/c~0d", "/key4/k/0/0"},
+		},
+		{
+			name: "Numbers Above Threshold",
+			match: func(e ast.Element) bool {
+				nl, ok := e.(*ast.NumberLiteral)
+				return ok && nl.Value > 0.75
+			},
+			expected: []string{"/key3/1", "/key4/k/0/0"},
+		},
+		{
+			name: "Root",
+			match: func(e ast.Element) bool {
+				_, ok := e.(*ast.Object)
+				return ok && e == root
+			},
+			expected: []string{""},
+		},
+		{
+			name:     "No Match",
+			match:    func(ast.Element) bool { return false },
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches := ast.FindAll(root, tt.match)
+
+			pointers := []string{}
+			for _, m := range matches {
+				assert.True(t, tt.match(m.Element), "match %q should satisfy the predicate", m.Pointer)
+				pointers = append(pointers, m.Pointer)
+			}
+			assert.Equal(t, tt.expected, pointers)
+		})
+	}
+}
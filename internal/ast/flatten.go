@@ -0,0 +1,231 @@
+package ast
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nobletk/json-parser/internal/token"
+)
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// EscapePointer escapes '~' and '/' in segment so it can be used as one
+// segment of a JSON Pointer, e.g. "a/b" becomes "a~1b".
+func EscapePointer(segment string) string {
+	return pointerEscaper.Replace(segment)
+}
+
+// UnescapeKey decodes the escapes of key, an object key as passed to the
+// function of Object.Each, e.g. `a\u002Fb` becomes "a/b". A key holding an
+// invalid escape is returned unchanged.
+func UnescapeKey(key string) string {
+	if str, err := unescape(key, false, false); err == nil {
+		return str
+	}
+	return key
+}
+
+// Flatten maps the JSON Pointer of every scalar in root to its Go value,
+// e.g. "/key3/2" to true. Strings are unescaped. Empty objects and arrays
+// are kept as leaves with an empty map or slice so the document can be
+// rebuilt with Unflatten.
+func Flatten(root Element) map[string]interface{} {
+	out := map[string]interface{}{}
+	flatten(root, "", out)
+	return out
+}
+
+func flatten(e Element, pointer string, out map[string]interface{}) {
+	switch e := e.(type) {
+	case *Object:
+		if len(e.Pairs) == 0 {
+			out[pointer] = map[string]interface{}{}
+			return
+		}
+		for _, k := range e.orderedKeys() {
+			flatten(e.Pairs[k], pointer+"/"+pointerEscaper.Replace(unescapedKey(k)), out)
+		}
+	case *ArrayLiteral:
+		if len(e.Elements) == 0 {
+			out[pointer] = []interface{}{}
+			return
+		}
+		for i, el := range e.Elements {
+			flatten(el, pointer+"/"+strconv.Itoa(i), out)
+		}
+	case *StringLiteral:
+		if str, err := e.Unescaped(); err == nil {
+			out[pointer] = str
+		} else {
+			out[pointer] = e.Value
+		}
+	case nil:
+	default:
+		out[pointer] = e.ToInterface()
+	}
+}
+
+type unflattenNode struct {
+	leaf     bool
+	value    interface{}
+	children map[string]*unflattenNode
+	// pairs counts the pointers that lead through the node, which bounds
+	// the length of an array built from it.
+	pairs int
+}
+
+// Unflatten is the inverse of Flatten. It rebuilds a document from JSON
+// Pointers, creating objects and arrays along the way. A container whose
+// segments are all array indexes becomes an array, with null filling any
+// index that has no value, but an index larger than the number of pairs
+// leading into the array is an error. Object keys are sorted since the map
+// has no order.
+func Unflatten(pairs map[string]interface{}) (Element, error) {
+	if len(pairs) == 0 {
+		return nil, fmt.Errorf("no paths to unflatten")
+	}
+
+	pointers := make([]string, 0, len(pairs))
+	for pointer := range pairs {
+		pointers = append(pointers, pointer)
+	}
+	sort.Strings(pointers)
+
+	root := &unflattenNode{}
+	for _, pointer := range pointers {
+		segments, err := splitPointer(pointer)
+		if err != nil {
+			return nil, err
+		}
+
+		node := root
+		for i, seg := range segments {
+			if node.leaf {
+				return nil, fmt.Errorf("conflicting paths: %q is a value but %q needs a container",
+					joinPointer(segments[:i]), pointer)
+			}
+			node.pairs++
+			if node.children == nil {
+				node.children = map[string]*unflattenNode{}
+			}
+			child, ok := node.children[seg]
+			if !ok {
+				child = &unflattenNode{}
+				node.children[seg] = child
+			}
+			node = child
+		}
+
+		if node.children != nil {
+			return nil, fmt.Errorf("conflicting paths: %q is a value but also has children", pointer)
+		}
+		node.leaf = true
+		node.value = pairs[pointer]
+	}
+
+	return root.element("")
+}
+
+func (n *unflattenNode) element(pointer string) (Element, error) {
+	if n.leaf {
+		e, err := FromInterface(n.value)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", pointer, err)
+		}
+		return e, nil
+	}
+
+	keys := make([]string, 0, len(n.children))
+	for k := range n.children {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	if length, ok := arrayLength(keys); ok {
+		if length-1 > n.pairs {
+			return nil, fmt.Errorf("%q: array index %d exceeds the number of pairs in the array (%d)",
+				pointer, length-1, n.pairs)
+		}
+		elements := make([]Element, length)
+		for i := range elements {
+			child, ok := n.children[strconv.Itoa(i)]
+			if !ok {
+				elements[i], _ = FromInterface(nil)
+				continue
+			}
+			e, err := child.element(pointer + "/" + strconv.Itoa(i))
+			if err != nil {
+				return nil, err
+			}
+			elements[i] = e
+		}
+		return &ArrayLiteral{Token: token.Token{Type: token.LBRACKET, Literal: "["}, Elements: elements}, nil
+	}
+
+	obj := &Object{
+		Token: token.Token{Type: token.LBRACE, Literal: "{"},
+		Pairs: make(map[Element]Element, len(keys)),
+	}
+	for _, k := range keys {
+		e, err := n.children[k].element(pointer + "/" + pointerEscaper.Replace(k))
+		if err != nil {
+			return nil, err
+		}
+		key, _ := FromInterface(k)
+		obj.Pairs[key] = e
+		obj.Keys = append(obj.Keys, key)
+	}
+	return obj, nil
+}
+
+// arrayLength reports whether keys are all array indexes and, if so, the
+// length of the array holding them.
+func arrayLength(keys []string) (int, bool) {
+	length := 0
+	for _, k := range keys {
+		i, err := strconv.Atoi(k)
+		if err != nil || i < 0 || strconv.Itoa(i) != k {
+			return 0, false
+		}
+		length = max(length, i+1)
+	}
+	return length, true
+}
+
+var pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+
+func splitPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON Pointer %q: must start with '/'", pointer)
+	}
+
+	segments := strings.Split(pointer[1:], "/")
+	for i, seg := range segments {
+		segments[i] = pointerUnescaper.Replace(seg)
+	}
+	return segments, nil
+}
+
+func joinPointer(segments []string) string {
+	var out strings.Builder
+	for _, seg := range segments {
+		out.WriteString("/" + pointerEscaper.Replace(seg))
+	}
+	return out.String()
+}
+
+// unescapedKey returns the decoded text of an object key, falling back to
+// the raw key when it holds an invalid escape.
+func unescapedKey(k Element) string {
+	if sl, ok := k.(*StringLiteral); ok {
+		if str, err := sl.Unescaped(); err == nil {
+			return str
+		}
+	}
+	return keyString(k)
+}
@@ -0,0 +1,184 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/nobletk/json-parser/internal/ast"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlatten(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected map[string]interface{}
+	}{
+		{
+			name: "Nested Document",
+			input: `{
+				"key1": "value",
+				"key3": ["value", 1, true, null],
+				"key4": {"key5": {"key6": -0.5}, "key7": [[false]]}
+			}`,
+			expected: map[string]interface{}{
+				"/key1":           "value",
+				"/key3/0":         "value",
+				"/key3/1":         float64(1),
+				"/key3/2":         true,
+				"/key3/3":         nil,
+				"/key4/key5/key6": -0.5,
+				"/key4/key7/0/0":  false,
+			},
+		},
+		{
+			name:  "Empty Containers",
+			input: `{"obj": {}, "arr": [], "nested": [{}]}`,
+			expected: map[string]interface{}{
+				"/obj":      map[string]interface{}{},
+				"/arr":      []interface{}{},
+				"/nested/0": map[string]interface{}{},
+			},
+		},
+		{
+			name:  "Escaped Pointer Keys",
+			input: `{"a/b": {"m~n": 1, "": 2, "c": 3}}`,
+			expected: map[string]interface{}{
+				"/a~1b/m~0n": float64(1),
+				"/a~1b/":     float64(2),
+				"/a~1b/c":    float64(3),
+			},
+		},
+		{
+			name:  "Empty Root",
+			input: `[]`,
+			expected: map[string]interface{}{
+				"": []interface{}{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ast.Flatten(parse(t, tt.input)))
+		})
+	}
+}
+
+func TestUnflattenRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{
+			name: "Nested Document",
+			input: `{"key1":"value","key3":["value",1,true,null],` +
+				`"key4":{"key5":{"key6":-0.5},"key7":[[false]]}}`,
+		},
+		{
+			name:  "Empty Containers",
+			input: `{"arr":[],"nested":[{},[]],"obj":{}}`,
+		},
+		{
+			name:  "Escapes In Keys And Strings",
+			input: `{"a/b":{"":2,"m~n":"say \"hi\"\n"}}`,
+		},
+		{
+			name:  "Numeric Looking Keys Beside Others",
+			input: `{"map":{"0":"zero","x":"ex"}}`,
+		},
+		{
+			name:  "Empty Root",
+			input: `{}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flat := ast.Flatten(parse(t, tt.input))
+
+			e, err := ast.Unflatten(flat)
+			require.NoError(t, err)
+
+			out, err := ast.Marshal(e)
+			require.NoError(t, err)
+			assert.Equal(t, tt.input, string(out))
+			assert.Equal(t, flat, ast.Flatten(e))
+		})
+	}
+}
+
+func TestUnflattenArrays(t *testing.T) {
+	e, err := ast.Unflatten(map[string]interface{}{
+		"/list/2":      "third",
+		"/list/0/name": "first",
+	})
+	require.NoError(t, err)
+
+	out, err := ast.Marshal(e)
+	require.NoError(t, err)
+	assert.Equal(t, `{"list":[{"name":"first"},null,"third"]}`, string(out))
+}
+
+func TestUnflattenErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    map[string]interface{}
+		expected string
+	}{
+		{
+			name:     "No Paths",
+			input:    map[string]interface{}{},
+			expected: "no paths to unflatten",
+		},
+		{
+			name:     "Scalar And Container",
+			input:    map[string]interface{}{"/a": 1, "/a/b": 2},
+			expected: `conflicting paths: "/a" is a value but "/a/b" needs a container`,
+		},
+		{
+			name:     "Empty Container With Children",
+			input:    map[string]interface{}{"/a": []interface{}{}, "/a/0": 2},
+			expected: `conflicting paths: "/a" is a value but "/a/0" needs a container`,
+		},
+		{
+			name:     "Root Value With Children",
+			input:    map[string]interface{}{"": 1, "/a": 2},
+			expected: `conflicting paths: "" is a value but "/a" needs a container`,
+		},
+		{
+			name:     "Invalid Pointer",
+			input:    map[string]interface{}{"a/b": 1},
+			expected: `invalid JSON Pointer "a/b": must start with '/'`,
+		},
+		{
+			name:     "Huge Array Index",
+			input:    map[string]interface{}{"/99999999999999999": 1},
+			expected: `"": array index 99999999999999999 exceeds the number of pairs in the array (1)`,
+		},
+		{
+			name:     "Sparse Array Index",
+			input:    map[string]interface{}{"/list/0": 1, "/list/5": 2},
+			expected: `"/list": array index 5 exceeds the number of pairs in the array (2)`,
+		},
+		{
+			name:     "Unsupported Value",
+			input:    map[string]interface{}{"/a/0": struct{}{}},
+			expected: `"/a/0": cannot convert struct {} to an element`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ast.Unflatten(tt.input)
+			assert.EqualError(t, err, tt.expected)
+		})
+	}
+}
+
+func TestPointerSegments(t *testing.T) {
+	assert.Equal(t, "a~1b~0c", ast.EscapePointer("a/b~c"))
+	assert.Equal(t, "a/b", ast.UnescapeKey(`a\u002Fb`))
+	assert.Equal(t, "a~1b", ast.EscapePointer(ast.UnescapeKey(`a\/b`)))
+	assert.Equal(t, `bad\q`, ast.UnescapeKey(`bad\q`))
+}
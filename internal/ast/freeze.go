@@ -0,0 +1,199 @@
+package ast
+
+import "math/big"
+
+// FrozenObject is a read-only view of an Object returned by Freeze. Its
+// pairs can only be read through its methods, and nested objects and arrays
+// are returned frozen as well.
+type FrozenObject struct {
+	obj *Object
+}
+
+// FrozenArray is a read-only view of an ArrayLiteral returned by Freeze.
+type FrozenArray struct {
+	arr *ArrayLiteral
+}
+
+// Freeze returns a deep copy of e that cannot be modified. Objects and
+// arrays become a *FrozenObject and a *FrozenArray, while scalars are
+// copied on every access so changing them never affects the frozen tree.
+// Later changes to e are not visible through the result either. Use Thaw
+// to get a mutable copy back.
+func Freeze(e Element) Element {
+	return freeze(deepCopy(e))
+}
+
+// freeze wraps an element that is already owned by a frozen tree.
+func freeze(e Element) Element {
+	switch e := e.(type) {
+	case *Object:
+		return &FrozenObject{obj: e}
+	case *ArrayLiteral:
+		return &FrozenArray{arr: e}
+	default:
+		return deepCopy(e)
+	}
+}
+
+// Thaw returns a mutable deep copy of a frozen element. Any other element
+// is returned as it is.
+func Thaw(e Element) Element {
+	switch e := e.(type) {
+	case *FrozenObject:
+		return deepCopy(e.obj)
+	case *FrozenArray:
+		return deepCopy(e.arr)
+	default:
+		return e
+	}
+}
+
+// unwrap returns the node a frozen element wraps, without copying it, for
+// code that only reads it. Any other element is returned as it is.
+func unwrap(e Element) Element {
+	switch e := e.(type) {
+	case *FrozenObject:
+		return e.obj
+	case *FrozenArray:
+		return e.arr
+	default:
+		return e
+	}
+}
+
+func (fo *FrozenObject) elementNode()             {}
+func (fo *FrozenObject) TokenLiteral() string     { return fo.obj.TokenLiteral() }
+func (fo *FrozenObject) String() string           { return fo.obj.String() }
+func (fo *FrozenObject) ToInterface() interface{} { return deepCopy(fo.obj).ToInterface() }
+
+// Len returns the number of pairs.
+func (fo *FrozenObject) Len() int { return len(fo.obj.Pairs) }
+
+// Keys returns the keys in the order they were parsed.
+func (fo *FrozenObject) Keys() []string {
+	keys := make([]string, 0, len(fo.obj.Pairs))
+	fo.obj.Each(func(key string, _ Element) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+// Get returns the value stored under key.
+func (fo *FrozenObject) Get(key string) (Element, bool) {
+	value, ok := objectValue(fo.obj, key)
+	if !ok {
+		return nil, false
+	}
+	return freeze(value), true
+}
+
+// Each works like Object.Each with frozen values.
+func (fo *FrozenObject) Each(fn func(key string, value Element) bool) {
+	fo.obj.Each(func(key string, value Element) bool {
+		return fn(key, freeze(value))
+	})
+}
+
+func (fa *FrozenArray) elementNode()             {}
+func (fa *FrozenArray) TokenLiteral() string     { return fa.arr.TokenLiteral() }
+func (fa *FrozenArray) String() string           { return fa.arr.String() }
+func (fa *FrozenArray) ToInterface() interface{} { return deepCopy(fa.arr).ToInterface() }
+
+// Len returns the number of elements.
+func (fa *FrozenArray) Len() int { return len(fa.arr.Elements) }
+
+// Index returns the element at i.
+func (fa *FrozenArray) Index(i int) (Element, bool) {
+	if i < 0 || i >= len(fa.arr.Elements) {
+		return nil, false
+	}
+	return freeze(fa.arr.Elements[i]), true
+}
+
+// Each calls fn for every element in order. Returning false from fn stops
+// the iteration.
+func (fa *FrozenArray) Each(fn func(i int, value Element) bool) {
+	for i, el := range fa.arr.Elements {
+		if !fn(i, freeze(el)) {
+			return
+		}
+	}
+}
+
+func deepCopy(e Element) Element {
+	switch e := e.(type) {
+	case *Object:
+		obj := &Object{
+			Comments: e.Comments.clone(),
+			Trivia:   e.Trivia,
+			Token:    e.Token,
+			Pairs:    make(map[Element]Element, len(e.Pairs)),
+		}
+		for _, k := range e.orderedKeys() {
+			key, value := deepCopy(k), deepCopy(e.Pairs[k])
+			copyLink(key, k, obj)
+			copyLink(value, e.Pairs[k], obj)
+			obj.Pairs[key] = value
+			obj.Keys = append(obj.Keys, key)
+		}
+		return obj
+	case *ArrayLiteral:
+		arr := &ArrayLiteral{
+			Comments: e.Comments.clone(),
+			Trivia:   e.Trivia,
+			Token:    e.Token,
+			Elements: make([]Element, 0, len(e.Elements)),
+		}
+		for _, el := range e.Elements {
+			c := deepCopy(el)
+			copyLink(c, el, arr)
+			arr.Elements = append(arr.Elements, c)
+		}
+		return arr
+	case *StringLiteral:
+		sl := *e
+		sl.Comments = e.Comments.clone()
+		sl.Links = Links{}
+		return &sl
+	case *NumberLiteral:
+		nl := *e
+		nl.Comments = e.Comments.clone()
+		nl.Links = Links{}
+		if e.Big != nil {
+			nl.Big = new(big.Rat).Set(e.Big)
+		}
+		return &nl
+	case *Boolean:
+		b := *e
+		b.Comments = e.Comments.clone()
+		b.Links = Links{}
+		return &b
+	case *Null:
+		n := *e
+		n.Comments = e.Comments.clone()
+		n.Links = Links{}
+		return &n
+	case *FrozenObject:
+		return deepCopy(e.obj)
+	case *FrozenArray:
+		return deepCopy(e.arr)
+	default:
+		return e
+	}
+}
+
+// copyLink links the copy c to parent when the original node it was copied
+// from was linked, so copies keep links without pointing into the original.
+func copyLink(c, original, parent Element) {
+	if Parent(original) != nil {
+		setParent(c, parent)
+	}
+}
+
+func (c Comments) clone() Comments {
+	return Comments{
+		LeadingComments:  append([]string(nil), c.LeadingComments...),
+		TrailingComments: append([]string(nil), c.TrailingComments...),
+	}
+}
@@ -0,0 +1,121 @@
+package ast_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nobletk/json-parser/internal/ast"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFreeze(t *testing.T) {
+	root := parse(t, `{"name": "value", "list": [1, {"deep": true}], "empty": null}`)
+	frozen := ast.Freeze(root)
+
+	obj, ok := frozen.(*ast.FrozenObject)
+	require.True(t, ok, "frozen object should be *ast.FrozenObject")
+	_, ok = frozen.(*ast.Object)
+	assert.False(t, ok, "frozen object should not expose *ast.Object")
+
+	assert.Equal(t, 3, obj.Len())
+	assert.Equal(t, []string{"name", "list", "empty"}, obj.Keys())
+	assert.Equal(t, root.ToInterface(), frozen.ToInterface())
+
+	name, ok := obj.Get("name")
+	require.True(t, ok)
+	assert.Equal(t, `"value"`, name.String())
+
+	_, ok = obj.Get("missing")
+	assert.False(t, ok)
+
+	list, ok := obj.Get("list")
+	require.True(t, ok)
+	arr, ok := list.(*ast.FrozenArray)
+	require.True(t, ok, "nested array should be frozen")
+	assert.Equal(t, 2, arr.Len())
+
+	nested, ok := arr.Index(1)
+	require.True(t, ok)
+	_, ok = nested.(*ast.FrozenObject)
+	assert.True(t, ok, "nested object should be frozen")
+
+	_, ok = arr.Index(2)
+	assert.False(t, ok)
+
+	out, err := ast.Marshal(frozen)
+	require.NoError(t, err)
+	assert.Equal(t, `{"name":"value","list":[1,{"deep":true}],"empty":null}`, string(out))
+}
+
+func TestFreezeBlocksMutation(t *testing.T) {
+	root := parse(t, `{"name": "value", "list": [1, 2]}`)
+	frozen := ast.Freeze(root).(*ast.FrozenObject)
+	expected := frozen.ToInterface()
+
+	// Changing a returned scalar does not reach the frozen tree.
+	name, _ := frozen.Get("name")
+	name.(*ast.StringLiteral).Value = "changed"
+
+	// Neither does changing the original tree.
+	original := root.(*ast.Object)
+	for k, v := range original.Pairs {
+		if arr, ok := v.(*ast.ArrayLiteral); ok {
+			arr.Elements = append(arr.Elements, arr.Elements[0])
+		}
+		delete(original.Pairs, k)
+	}
+
+	assert.Equal(t, expected, frozen.ToInterface())
+
+	// Thaw hands out a copy that can be changed freely.
+	thawed := ast.Thaw(frozen).(*ast.Object)
+	thawed.Pairs = nil
+	assert.Equal(t, expected, frozen.ToInterface())
+}
+
+func TestFreezeReadHelpers(t *testing.T) {
+	root := parse(t, `{"a": [1, {"b\/c": "x"}], "d\"e": null}`)
+	frozen := ast.Freeze(root)
+
+	assert.Equal(t, ast.Stats(root), ast.Stats(frozen))
+	assert.Equal(t, ast.Dump(root), strings.NewReplacer("Frozen ", "").Replace(ast.Dump(frozen)))
+
+	e, err := ast.Select(frozen, `a[1]["b/c"]`)
+	require.NoError(t, err)
+	assert.Equal(t, `"x"`, e.String())
+
+	e, err = ast.Select(frozen, "a[1]")
+	require.NoError(t, err)
+	_, ok := e.(*ast.FrozenObject)
+	assert.True(t, ok, "selected object should be frozen")
+
+	_, err = ast.Select(frozen, "a[2]")
+	assert.EqualError(t, err, `index 2 out of range at "a" (length 2)`)
+
+	value, ok := frozen.(*ast.FrozenObject).Get(`d"e`)
+	require.True(t, ok)
+	assert.Equal(t, "null", value.String())
+
+	isString := func(e ast.Element) bool {
+		_, ok := e.(*ast.StringLiteral)
+		return ok
+	}
+	matches := ast.FindAll(frozen, isString)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "/a/1/b~1c", matches[0].Pointer)
+
+	var paths []string
+	ast.Walk(frozen, func(path []string, e ast.Element) bool {
+		paths = append(paths, strings.Join(path, "."))
+		return true
+	})
+	assert.Equal(t, []string{"", "a", "a.0", "a.1", `a.1.b\/c`, `d\"e`}, paths)
+
+	assert.True(t, ast.Equal(frozen, root))
+	assert.True(t, ast.Equal(root, frozen))
+	assert.True(t, ast.EqualInterface(frozen, map[string]interface{}{
+		"a":   []interface{}{1, map[string]interface{}{"b/c": "x"}},
+		`d"e`: nil,
+	}))
+}
@@ -0,0 +1,98 @@
+package ast
+
+import (
+	"crypto/sha256"
+	"io"
+	"math/big"
+	"sort"
+	"strconv"
+)
+
+// Hash returns the SHA-256 of a canonical form of e, so documents that
+// only differ in formatting, key order, string escapes or how a number is
+// written (1, 1.0 and 1e0) hash the same.
+func Hash(e Element) [32]byte {
+	h := sha256.New()
+	writeCanonical(h, e)
+
+	var sum [32]byte
+	copy(sum[:], h.Sum(nil))
+	return sum
+}
+
+func writeCanonical(w io.Writer, e Element) {
+	switch e := e.(type) {
+	case *Object:
+		keys := make([]string, 0, len(e.Pairs))
+		values := make(map[string]Element, len(e.Pairs))
+		for _, k := range e.orderedKeys() {
+			key := unescapedKey(k)
+			keys = append(keys, key)
+			values[key] = e.Pairs[k]
+		}
+		sort.Strings(keys)
+
+		io.WriteString(w, "{")
+		for i, key := range keys {
+			if i > 0 {
+				io.WriteString(w, ",")
+			}
+			io.WriteString(w, EscapeString(key, false)+":")
+			writeCanonical(w, values[key])
+		}
+		io.WriteString(w, "}")
+	case *ArrayLiteral:
+		io.WriteString(w, "[")
+		for i, el := range e.Elements {
+			if i > 0 {
+				io.WriteString(w, ",")
+			}
+			writeCanonical(w, el)
+		}
+		io.WriteString(w, "]")
+	case *FrozenObject:
+		writeCanonical(w, e.obj)
+	case *FrozenArray:
+		writeCanonical(w, e.arr)
+	case *StringLiteral:
+		str, err := e.Unescaped()
+		if err != nil {
+			str = e.Value
+		}
+		io.WriteString(w, EscapeString(str, false))
+	case *NumberLiteral:
+		io.WriteString(w, canonicalNumber(e))
+	case *Boolean:
+		io.WriteString(w, strconv.FormatBool(e.Value))
+	case *Null:
+		io.WriteString(w, "null")
+	}
+}
+
+// canonicalNumber writes a number as its significant digits and a decimal
+// exponent, e.g. both 1.50 and 15e-1 become "15e-1".
+func canonicalNumber(nl *NumberLiteral) string {
+	literal := nl.Token.Literal
+	if nl.IsHex() {
+		if n, ok := new(big.Int).SetString(literal, 0); ok {
+			literal = n.String()
+		}
+	}
+	if literal == "" {
+		literal = strconv.FormatFloat(nl.Value, 'g', -1, 64)
+	}
+
+	neg, digits, point, ok := splitNumber(literal)
+	if !ok {
+		return literal
+	}
+	if len(digits) == 0 {
+		return "0"
+	}
+
+	sign := ""
+	if neg {
+		sign = "-"
+	}
+	return sign + digits + "e" + strconv.Itoa(point-len(digits))
+}
@@ -0,0 +1,89 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/nobletk/json-parser/internal/ast"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHash(t *testing.T) {
+	tests := []struct {
+		name  string
+		a     string
+		b     string
+		equal bool
+	}{
+		{
+			name:  "Reordered Keys",
+			a:     `{"a": 1, "b": {"x": [true, null], "y": "s"}}`,
+			b:     `{"b": {"y": "s", "x": [true, null]}, "a": 1}`,
+			equal: true,
+		},
+		{
+			name:  "Different Formatting",
+			a:     `{"a":[1,2,3]}`,
+			b:     "{\n  \"a\": [\n    1,\n    2,\n    3\n  ]\n}",
+			equal: true,
+		},
+		{
+			name:  "Normalized Numbers",
+			a:     `[1, 1.50, 100, 0, 0.001]`,
+			b:     `[1.0, 15e-1, 1E2, -0.0, 1e-3]`,
+			equal: true,
+		},
+		{
+			name:  "Normalized Strings",
+			a:     `{"kéy": "a\/b"}`,
+			b:     `{"kéy": "a/b"}`,
+			equal: true,
+		},
+		{
+			name:  "Changed Value",
+			a:     `{"a": 1, "b": {"x": [true, null]}}`,
+			b:     `{"a": 1, "b": {"x": [false, null]}}`,
+			equal: false,
+		},
+		{
+			name:  "Changed Number",
+			a:     `[1.5]`,
+			b:     `[-1.5]`,
+			equal: false,
+		},
+		{
+			name:  "Reordered Array",
+			a:     `[1, 2]`,
+			b:     `[2, 1]`,
+			equal: false,
+		},
+		{
+			name:  "String Versus Number",
+			a:     `["1"]`,
+			b:     `[1]`,
+			equal: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := ast.Hash(parse(t, tt.a))
+			b := ast.Hash(parse(t, tt.b))
+
+			if tt.equal {
+				assert.Equal(t, a, b)
+			} else {
+				assert.NotEqual(t, a, b)
+			}
+		})
+	}
+}
+
+func TestHashStable(t *testing.T) {
+	root := parse(t, `{"z": 1, "a": 2, "m": {"c": 3, "b": 4}}`)
+	expected := ast.Hash(root)
+
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, expected, ast.Hash(root), "run %d", i)
+	}
+	assert.Equal(t, expected, ast.Hash(ast.Freeze(root)), "frozen documents should hash the same")
+}
@@ -0,0 +1,29 @@
+package ast
+
+// The MarshalJSON methods make the AST a json.Marshaler, so nodes can be
+// handed to encoding/json directly. Unlike ToInterface they keep the parsed
+// key order. Comments are dropped and strings are normalized so the output
+// is always valid JSON, even for input parsed with extensions.
+
+func marshalJSON(e Element) ([]byte, error) {
+	m := &Marshaler{NormalizeStrings: true, OmitComments: true}
+	return m.Marshal(e)
+}
+
+// MarshalJSON encodes the first element like ToInterface does, or null when
+// the file is empty.
+func (jf *JSONFile) MarshalJSON() ([]byte, error) {
+	if len(jf.Elements) == 0 {
+		return []byte("null"), nil
+	}
+	return marshalJSON(jf.Elements[0])
+}
+
+func (o *Object) MarshalJSON() ([]byte, error)         { return marshalJSON(o) }
+func (al *ArrayLiteral) MarshalJSON() ([]byte, error)  { return marshalJSON(al) }
+func (sl *StringLiteral) MarshalJSON() ([]byte, error) { return marshalJSON(sl) }
+func (nl *NumberLiteral) MarshalJSON() ([]byte, error) { return marshalJSON(nl) }
+func (b *Boolean) MarshalJSON() ([]byte, error)        { return marshalJSON(b) }
+func (n *Null) MarshalJSON() ([]byte, error)           { return marshalJSON(n) }
+func (fo *FrozenObject) MarshalJSON() ([]byte, error)  { return marshalJSON(fo) }
+func (fa *FrozenArray) MarshalJSON() ([]byte, error)   { return marshalJSON(fa) }
@@ -0,0 +1,74 @@
+package ast_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/nobletk/json-parser/internal/ast"
+	"github.com/nobletk/json-parser/internal/lexer"
+	"github.com/nobletk/json-parser/internal/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		lexerOpts  []lexer.Option
+		parserOpts []parser.Option
+		expected   string
+	}{
+		{
+			name:     "Preserves Key Order",
+			input:    `{"z": 1, "a": [true, null, "s"], "m": {"y": -0.5, "b": {}}}`,
+			expected: `{"z":1,"a":[true,null,"s"],"m":{"y":-0.5,"b":{}}}`,
+		},
+		{
+			name:     "Escapes Kept Valid",
+			input:    `{"k\"ey": "line\nbreak é \/"}`,
+			expected: `{"k\"ey":"line\nbreak é /"}`,
+		},
+		{
+			name:      "Comments Dropped",
+			input:     "{\n  // note\n  \"a\": 1 /* one */\n}",
+			lexerOpts: []lexer.Option{lexer.WithComments()},
+			expected:  `{"a":1}`,
+		},
+		{
+			name:       "Extensions Become JSON",
+			input:      `["\x41", 0x10, undefined]`,
+			lexerOpts:  []lexer.Option{lexer.AllowHexNumbers()},
+			parserOpts: []parser.Option{parser.AllowExtendedEscapes(), parser.AllowUndefined()},
+			expected:   `["A",16,null]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := lexer.New(nil, tt.input, tt.lexerOpts...)
+			jf, jErr := parser.New(l, tt.parserOpts...).ParseFile()
+			require.Empty(t, jErr, "jsonErr should be empty")
+
+			out, err := json.Marshal(jf)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(out))
+
+			out, err = json.Marshal(jf.Elements[0])
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(out))
+		})
+	}
+}
+
+func TestMarshalJSONNested(t *testing.T) {
+	doc := parse(t, `{"b": 1, "a": 2}`)
+
+	out, err := json.Marshal(map[string]interface{}{
+		"doc":    doc,
+		"frozen": ast.Freeze(doc),
+		"empty":  &ast.JSONFile{},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `{"doc":{"b":1,"a":2},"empty":null,"frozen":{"b":1,"a":2}}`, string(out))
+}
@@ -0,0 +1,343 @@
+package ast
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Marshaler writes an AST back out as JSON text. Object keys are emitted
+// in the order they were parsed and attached comments are re-emitted next
+// to the node they belong to.
+type Marshaler struct {
+	// Indent is repeated once per nesting level. An empty Indent produces
+	// compact output.
+	Indent string
+
+	// NormalizeStrings rewrites strings so they only use the escapes JSON
+	// requires, e.g. "a\/b" becomes "a/b" while "\"" and "\\" are kept.
+	NormalizeStrings bool
+
+	// NormalizeNumbers writes numbers in the shortest form that parses back
+	// to the same float64, e.g. "-0.2e2" becomes "-20", "1.000" becomes "1"
+	// and "-0" becomes "0". Digits beyond float64 precision are lost.
+	// Otherwise the original literal is kept.
+	NormalizeNumbers bool
+
+	// Align pads the keys of each object to the same width so their colons
+	// line up. It only applies to indented output.
+	Align bool
+
+	// OmitComments drops attached comments so the output is plain JSON.
+	OmitComments bool
+
+	// ASCIIOnly escapes every non-ASCII character as \uXXXX, using a
+	// surrogate pair beyond the Basic Multilingual Plane, so the output is
+	// safe for channels that are not 8-bit clean. Otherwise such characters
+	// are written as raw UTF-8.
+	ASCIIOnly bool
+
+	// MaxWidth, when set with Indent, keeps an object or array on one line,
+	// e.g. {"a": 1, "b": [2, 3]}, if the line it ends then fits within
+	// MaxWidth columns. Larger ones are broken over lines as usual and the
+	// same choice is made again for each of their values. Objects and
+	// arrays holding comments are always broken unless OmitComments is set.
+	MaxWidth int
+
+	// PreserveTrivia writes the whitespace and comments recorded by
+	// parser.WithTrivia instead of formatting the output, and keeps number
+	// and literal tokens as they were written. Indent, Align, OmitComments
+	// and MaxWidth are ignored. Nodes without trivia are written compactly.
+	PreserveTrivia bool
+}
+
+func Marshal(e Element) ([]byte, error) {
+	m := &Marshaler{}
+	return m.Marshal(e)
+}
+
+func MarshalIndent(e Element, indent string) ([]byte, error) {
+	m := &Marshaler{Indent: indent}
+	return m.Marshal(e)
+}
+
+func MarshalAligned(e Element, indent string) ([]byte, error) {
+	m := &Marshaler{Indent: indent, Align: true}
+	return m.Marshal(e)
+}
+
+// MarshalWrapped writes e indented like MarshalIndent, except that objects
+// and arrays that fit within maxWidth columns stay on one line, see
+// Marshaler.MaxWidth.
+func MarshalWrapped(e Element, indent string, maxWidth int) ([]byte, error) {
+	m := &Marshaler{Indent: indent, MaxWidth: maxWidth}
+	return m.Marshal(e)
+}
+
+func (m *Marshaler) Marshal(e Element) ([]byte, error) {
+	var out bytes.Buffer
+
+	if m.PreserveTrivia {
+		if err := m.writeWithTrivia(&out, e); err != nil {
+			return nil, err
+		}
+		return out.Bytes(), nil
+	}
+
+	m.writeComments(&out, LeadingComments(e), 0, true)
+	if err := m.writeValue(&out, e, 0, false); err != nil {
+		return nil, err
+	}
+	m.writeTrailingComments(&out, TrailingComments(e), 0, true)
+
+	return out.Bytes(), nil
+}
+
+func (m *Marshaler) writeElement(out *bytes.Buffer, e Element, depth int) error {
+	switch e := e.(type) {
+	case *Object:
+		return m.writeObject(out, e, depth)
+	case *ArrayLiteral:
+		return m.writeArray(out, e, depth)
+	case *FrozenObject:
+		return m.writeObject(out, e.obj, depth)
+	case *FrozenArray:
+		return m.writeArray(out, e.arr, depth)
+	case *StringLiteral:
+		return m.writeString(out, e)
+	case *NumberLiteral:
+		m.writeNumber(out, e)
+	case *Boolean:
+		out.WriteString(strconv.FormatBool(e.Value))
+	case *Null:
+		out.WriteString("null")
+	case nil:
+		return fmt.Errorf("cannot marshal a nil element")
+	default:
+		return fmt.Errorf("cannot marshal element of type %T", e)
+	}
+	return nil
+}
+
+// writeNumber keeps the original literal, except for hexadecimal literals
+// which JSON does not allow and are written in decimal instead.
+func (m *Marshaler) writeNumber(out *bytes.Buffer, nl *NumberLiteral) {
+	if m.NormalizeNumbers {
+		value := nl.Value
+		if value == 0 {
+			value = 0 // drops the sign of -0
+		}
+		out.WriteString(strconv.FormatFloat(value, 'g', -1, 64))
+		return
+	}
+
+	if nl.IsHex() {
+		if n, ok := new(big.Int).SetString(nl.Token.Literal, 0); ok {
+			out.WriteString(n.String())
+			return
+		}
+	}
+
+	// A leading '+' is only accepted with lexer.AllowLeadingPlus and is not
+	// valid JSON, so it is dropped.
+	if nl.Token.Literal != "" {
+		out.WriteString(strings.TrimPrefix(nl.Token.Literal, "+"))
+	} else {
+		out.WriteString(strconv.FormatFloat(nl.Value, 'g', -1, 64))
+	}
+}
+
+func (m *Marshaler) writeString(out *bytes.Buffer, sl *StringLiteral) error {
+	// \xHH, \0 and \u{XXXX} escapes are not JSON, so strings that may
+	// hold them are always normalized.
+	if m.NormalizeStrings || sl.ExtendedEscapes || sl.BraceUnicode {
+		unescaped, err := sl.Unescaped()
+		if err != nil {
+			return err
+		}
+		out.WriteString(EscapeString(unescaped, m.ASCIIOnly))
+		return nil
+	}
+
+	str := sl.Value
+	if m.ASCIIOnly {
+		str = escapeNonASCII(str)
+	}
+
+	out.WriteString(`"` + str + `"`)
+	return nil
+}
+
+// escapeNonASCII replaces the non-ASCII characters of an already escaped
+// string with \u escapes. Invalid UTF-8 becomes \ufffd.
+func escapeNonASCII(str string) string {
+	var out strings.Builder
+
+	for _, r := range str {
+		switch {
+		case r < utf8.RuneSelf:
+			out.WriteRune(r)
+		case r > 0xffff:
+			hi, lo := utf16.EncodeRune(r)
+			fmt.Fprintf(&out, "\\u%04x\\u%04x", hi, lo)
+		default:
+			fmt.Fprintf(&out, "\\u%04x", r)
+		}
+	}
+
+	return out.String()
+}
+
+func (m *Marshaler) writeObject(out *bytes.Buffer, o *Object, depth int) error {
+	keys := o.orderedKeys()
+	for _, key := range keys {
+		if _, ok := key.(*StringLiteral); !ok {
+			return fmt.Errorf("object key must be *StringLiteral, got %T", key)
+		}
+	}
+
+	widths, err := m.keyWidths(keys)
+	if err != nil {
+		return err
+	}
+	width := 0
+	for _, w := range widths {
+		width = max(width, w)
+	}
+
+	out.WriteString("{")
+
+	for i, key := range keys {
+		value := o.Pairs[key]
+
+		m.newline(out, depth+1)
+		m.writeComments(out, LeadingComments(key), depth+1, true)
+		if err := m.writeElement(out, key, depth+1); err != nil {
+			return err
+		}
+		m.writeTrailingComments(out, TrailingComments(key), depth+1, false)
+		if widths != nil {
+			out.WriteString(strings.Repeat(" ", width-widths[i]))
+		}
+
+		out.WriteString(":")
+		if m.Indent != "" {
+			out.WriteString(" ")
+		}
+
+		m.writeComments(out, LeadingComments(value), depth+1, false)
+		if err := m.writeValue(out, value, depth+1, i < len(keys)-1); err != nil {
+			return err
+		}
+
+		if i < len(keys)-1 {
+			out.WriteString(",")
+		}
+		m.writeTrailingComments(out, TrailingComments(value), depth+1, m.Indent != "")
+	}
+
+	if len(keys) > 0 {
+		m.newline(out, depth)
+	}
+	out.WriteString("}")
+
+	return nil
+}
+
+// keyWidths returns the width in runes of every key as written, or nil
+// when keys are not aligned.
+func (m *Marshaler) keyWidths(keys []Element) ([]int, error) {
+	if !m.Align || m.Indent == "" {
+		return nil, nil
+	}
+
+	widths := make([]int, len(keys))
+	for i, key := range keys {
+		var buf bytes.Buffer
+		if err := m.writeString(&buf, key.(*StringLiteral)); err != nil {
+			return nil, err
+		}
+		widths[i] = utf8.RuneCount(buf.Bytes())
+	}
+	return widths, nil
+}
+
+func (m *Marshaler) writeArray(out *bytes.Buffer, al *ArrayLiteral, depth int) error {
+	out.WriteString("[")
+
+	for i, el := range al.Elements {
+		m.newline(out, depth+1)
+		m.writeComments(out, LeadingComments(el), depth+1, true)
+		if err := m.writeValue(out, el, depth+1, i < len(al.Elements)-1); err != nil {
+			return err
+		}
+
+		if i < len(al.Elements)-1 {
+			out.WriteString(",")
+		}
+		m.writeTrailingComments(out, TrailingComments(el), depth+1, m.Indent != "")
+	}
+
+	if len(al.Elements) > 0 {
+		m.newline(out, depth)
+	}
+	out.WriteString("]")
+
+	return nil
+}
+
+// writeComments writes comments that precede a node. When ownLine is set
+// and the output is indented every comment goes on its own line, otherwise
+// block comments stay inline and only line comments force a line break.
+func (m *Marshaler) writeComments(out *bytes.Buffer, comments []string, depth int, ownLine bool) {
+	if m.OmitComments {
+		return
+	}
+
+	for _, c := range comments {
+		out.WriteString(c)
+
+		if isLineComment(c) || (ownLine && m.Indent != "") {
+			m.forceNewline(out, depth)
+		} else {
+			out.WriteString(" ")
+		}
+	}
+}
+
+// writeTrailingComments writes comments that follow a node on the same
+// line. A trailing line comment is closed with a line break unless the
+// node already ends the line.
+func (m *Marshaler) writeTrailingComments(out *bytes.Buffer, comments []string, depth int, atLineEnd bool) {
+	if m.OmitComments {
+		return
+	}
+
+	for i, c := range comments {
+		out.WriteString(" " + c)
+
+		if isLineComment(c) && (i < len(comments)-1 || !atLineEnd) {
+			m.forceNewline(out, depth)
+		}
+	}
+}
+
+func (m *Marshaler) newline(out *bytes.Buffer, depth int) {
+	if m.Indent == "" {
+		return
+	}
+	m.forceNewline(out, depth)
+}
+
+func (m *Marshaler) forceNewline(out *bytes.Buffer, depth int) {
+	out.WriteString("\n")
+	out.WriteString(strings.Repeat(m.Indent, depth))
+}
+
+func isLineComment(c string) bool {
+	return strings.HasPrefix(c, "//")
+}
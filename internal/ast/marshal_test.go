@@ -0,0 +1,318 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/nobletk/json-parser/internal/ast"
+	"github.com/nobletk/json-parser/internal/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalNormalizeStrings(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		normalize bool
+		expected  string
+	}{
+		{
+			name:      "Escaped Solidus Preserved",
+			input:     `["a\/b"]`,
+			normalize: false,
+			expected:  `["a\/b"]`,
+		},
+		{
+			name:      "Escaped Solidus Normalized",
+			input:     `["a\/b"]`,
+			normalize: true,
+			expected:  `["a/b"]`,
+		},
+		{
+			name:      "Unicode Escapes Normalized",
+			input:     `{"key": "caf\u00e9"}`,
+			normalize: true,
+			expected:  `{"key":"café"}`,
+		},
+		{
+			name:      "Required Escapes Kept",
+			input:     `["\"quoted\" \\ \/ \b\f\n\r\t \u0001"]`,
+			normalize: true,
+			expected:  `["\"quoted\" \\ / \b\f\n\r\t \u0001"]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := parse(t, tt.input)
+
+			m := &ast.Marshaler{NormalizeStrings: tt.normalize}
+			out, err := m.Marshal(root)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(out))
+		})
+	}
+}
+
+func TestMarshalControlCharactersRoundTrip(t *testing.T) {
+	input := `["nul\u0000del\u007fend"]`
+
+	root := parse(t, input)
+	m := &ast.Marshaler{NormalizeStrings: true}
+	out, err := m.Marshal(root)
+	require.NoError(t, err)
+	assert.Equal(t, "[\"nul\\u0000del\x7fend\"]", string(out))
+
+	reparsed := parse(t, string(out))
+	before, err := root.(*ast.ArrayLiteral).Elements[0].(*ast.StringLiteral).Unescaped()
+	require.NoError(t, err)
+	after, err := reparsed.(*ast.ArrayLiteral).Elements[0].(*ast.StringLiteral).Unescaped()
+	require.NoError(t, err)
+
+	assert.Equal(t, "nul\x00del\x7fend", after)
+	assert.Equal(t, before, after)
+}
+
+func TestMarshalAligned(t *testing.T) {
+	input := `{"a": 1, "abc": {"x": true, "long key": null}, "ab": [{"é": 1, "ee": 2}]}`
+	expected := `{
+  "a"  : 1,
+  "abc": {
+    "x"       : true,
+    "long key": null
+  },
+  "ab" : [
+    {
+      "é" : 1,
+      "ee": 2
+    }
+  ]
+}`
+
+	root := parse(t, input)
+	out, err := ast.MarshalAligned(root, "  ")
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(out))
+
+	reparsed := parse(t, string(out))
+	assert.Equal(t, root.ToInterface(), reparsed.ToInterface(), "aligned output should re-parse to the same document")
+
+	again, err := ast.MarshalAligned(reparsed, "  ")
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(again))
+}
+
+func TestMarshalAlignedCompact(t *testing.T) {
+	m := &ast.Marshaler{Align: true}
+	out, err := m.Marshal(parse(t, `{"a": 1, "abc": 2}`))
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":1,"abc":2}`, string(out))
+}
+
+func TestMarshalASCIIOnly(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		marshaler ast.Marshaler
+		expected  string
+	}{
+		{
+			name:      "Escaped Emoji To UTF-8",
+			input:     `["\ud83d\ude00"]`,
+			marshaler: ast.Marshaler{NormalizeStrings: true},
+			expected:  `["😀"]`,
+		},
+		{
+			name:      "Escaped Emoji Kept ASCII",
+			input:     `["\ud83d\ude00"]`,
+			marshaler: ast.Marshaler{NormalizeStrings: true, ASCIIOnly: true},
+			expected:  `["\ud83d\ude00"]`,
+		},
+		{
+			name:      "Raw Emoji To Surrogate Pair",
+			input:     `["😀"]`,
+			marshaler: ast.Marshaler{ASCIIOnly: true},
+			expected:  `["\ud83d\ude00"]`,
+		},
+		{
+			name:      "BMP Characters And Keys",
+			input:     `{"clé": "naïve \n"}`,
+			marshaler: ast.Marshaler{ASCIIOnly: true},
+			expected:  `{"cl\u00e9":"na\u00efve \n"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := parse(t, tt.input)
+
+			out, err := tt.marshaler.Marshal(doc)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(out))
+
+			assert.True(t, ast.Equal(doc, parse(t, string(out))), "output should decode to the input")
+		})
+	}
+}
+
+func TestMarshalNormalizeNumbers(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "Exponent", input: `[-0.2e2]`, expected: `[-20]`},
+		{name: "Trailing Zeros", input: `[1.000]`, expected: `[1]`},
+		{name: "Negative Zero", input: `[-0, -0.0]`, expected: `[0,0]`},
+		{name: "Fraction", input: `[1.50, 0.1e-1]`, expected: `[1.5,0.01]`},
+		{name: "Large Number", input: `{"n": 1000000000000000000000}`, expected: `{"n":1e+21}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &ast.Marshaler{NormalizeNumbers: true}
+			out, err := m.Marshal(parse(t, tt.input))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(out))
+
+			original, err := ast.Marshal(parse(t, tt.input))
+			require.NoError(t, err)
+			assert.NotEqual(t, tt.expected, string(original), "literals should be kept by default")
+		})
+	}
+}
+
+func TestMarshalWrapped(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		maxWidth int
+		expected string
+	}{
+		{
+			name:     "Short Array Stays Inline",
+			input:    `[1, 2, 3]`,
+			maxWidth: 20,
+			expected: `[1, 2, 3]`,
+		},
+		{
+			name:     "Long Array Wraps",
+			input:    `["alpha", "beta", "gamma", "delta"]`,
+			maxWidth: 20,
+			expected: `[
+  "alpha",
+  "beta",
+  "gamma",
+  "delta"
+]`,
+		},
+		{
+			name:     "Nested Values Decide On Their Own",
+			input:    `{"point": {"x": 1, "y": 2}, "tags": ["a", "b"], "names": ["first name", "last name"]}`,
+			maxWidth: 30,
+			expected: `{
+  "point": {"x": 1, "y": 2},
+  "tags": ["a", "b"],
+  "names": [
+    "first name",
+    "last name"
+  ]
+}`,
+		},
+		{
+			name:     "Width Counts The Comma",
+			input:    `[[1, 2, 3], [4]]`,
+			maxWidth: 11,
+			expected: `[
+  [
+    1,
+    2,
+    3
+  ],
+  [4]
+]`,
+		},
+		{
+			name:     "Fits Exactly",
+			input:    `[[1, 2, 3], [4]]`,
+			maxWidth: 12,
+			expected: `[
+  [1, 2, 3],
+  [4]
+]`,
+		},
+		{
+			name:     "Width Counts Runes",
+			input:    `{"ééé": ["ééé", "ééé"]}`,
+			maxWidth: 23,
+			expected: `{"ééé": ["ééé", "ééé"]}`,
+		},
+		{
+			name:     "Empty Containers",
+			input:    `{"a": {}, "b": []}`,
+			maxWidth: 80,
+			expected: `{"a": {}, "b": []}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := parse(t, tt.input)
+			out, err := ast.MarshalWrapped(root, "  ", tt.maxWidth)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(out))
+
+			reparsed := parse(t, string(out))
+			assert.Equal(t, root.ToInterface(), reparsed.ToInterface(), "wrapped output should re-parse to the same document")
+		})
+	}
+}
+
+func TestMarshalWrappedComments(t *testing.T) {
+	arr := parse(t, `[1, 2]`).(*ast.ArrayLiteral)
+	ast.AddTrailingComments(arr.Elements[0], "// one")
+	root := ast.NewObject().Set("a", arr)
+
+	out, err := ast.MarshalWrapped(root, "  ", 80)
+	require.NoError(t, err)
+	assert.Equal(t, "{\n  \"a\": [\n    1, // one\n    2\n  ]\n}", string(out))
+
+	m := &ast.Marshaler{Indent: "  ", MaxWidth: 80, OmitComments: true}
+	out, err = m.Marshal(root)
+	require.NoError(t, err)
+	assert.Equal(t, `{"a": [1, 2]}`, string(out))
+}
+
+func TestMarshalWrappedCompact(t *testing.T) {
+	out, err := ast.MarshalWrapped(parse(t, `{"a": [1, 2]}`), "", 80)
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":[1,2]}`, string(out))
+}
+
+func TestMarshalBraceUnicodeRoundTrip(t *testing.T) {
+	input := `{"key": "\u{41}\u{1F600} and \u00e9"}`
+
+	jf, jErr := parser.ParseString(input, parser.AllowBraceUnicode())
+	require.Nil(t, jErr)
+
+	out, err := ast.Marshal(jf.Elements[0])
+	require.NoError(t, err)
+	assert.Equal(t, `{"key":"A😀 and é"}`, string(out))
+
+	reparsed := parse(t, string(out))
+	assert.Equal(t, map[string]interface{}{"key": "A😀 and é"}, reparsed.ToInterface())
+}
+
+func TestMarshalExtendedEscapesRoundTrip(t *testing.T) {
+	input := `["\x41\x62c", "nul\0end", "\xe9"]`
+
+	jf, jErr := parser.ParseString(input, parser.AllowExtendedEscapes())
+	require.Nil(t, jErr)
+
+	out, err := ast.Marshal(jf.Elements[0])
+	require.NoError(t, err)
+	assert.Equal(t, `["Abc","nul\u0000end","é"]`, string(out))
+
+	reparsed := parse(t, string(out))
+	assert.Equal(t, []interface{}{"Abc", `nul\u0000end`, "é"}, reparsed.ToInterface())
+}
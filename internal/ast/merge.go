@@ -0,0 +1,99 @@
+package ast
+
+import "fmt"
+
+// Merger deep-merges documents, e.g. layered configuration files. Later
+// documents override earlier ones and objects are merged key by key.
+type Merger struct {
+	// ConcatArrays appends arrays of later documents to those of earlier
+	// ones instead of replacing them.
+	ConcatArrays bool
+}
+
+func MergeAll(docs ...Element) (Element, error) {
+	m := &Merger{}
+	return m.MergeAll(docs...)
+}
+
+// MergeAll merges docs in order. The inputs are left untouched, but the
+// result may share nodes with them.
+func (m *Merger) MergeAll(docs ...Element) (Element, error) {
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("no documents to merge")
+	}
+
+	result := docs[0]
+	if result == nil {
+		return nil, fmt.Errorf("cannot merge a nil document at index 0")
+	}
+
+	for i, doc := range docs[1:] {
+		if doc == nil {
+			return nil, fmt.Errorf("cannot merge a nil document at index %d", i+1)
+		}
+
+		merged, err := m.merge(result, doc)
+		if err != nil {
+			return nil, err
+		}
+		result = merged
+	}
+
+	return result, nil
+}
+
+func (m *Merger) merge(base, override Element) (Element, error) {
+	switch override := override.(type) {
+	case *Object:
+		if base, ok := base.(*Object); ok {
+			return m.mergeObjects(base, override)
+		}
+	case *ArrayLiteral:
+		if base, ok := base.(*ArrayLiteral); ok && m.ConcatArrays {
+			elements := make([]Element, 0, len(base.Elements)+len(override.Elements))
+			elements = append(elements, base.Elements...)
+			elements = append(elements, override.Elements...)
+			return &ArrayLiteral{Token: base.Token, Elements: elements}, nil
+		}
+	}
+	return override, nil
+}
+
+func (m *Merger) mergeObjects(base, override *Object) (*Object, error) {
+	out := &Object{
+		Token: base.Token,
+		Pairs: make(map[Element]Element, len(base.Pairs)+len(override.Pairs)),
+	}
+	index := make(map[string]Element, len(base.Pairs))
+
+	for _, k := range base.orderedKeys() {
+		if _, ok := k.(*StringLiteral); !ok {
+			return nil, fmt.Errorf("object key must be *StringLiteral, got %T", k)
+		}
+		out.Pairs[k] = base.Pairs[k]
+		out.Keys = append(out.Keys, k)
+		index[unescapedKey(k)] = k
+	}
+
+	for _, k := range override.orderedKeys() {
+		if _, ok := k.(*StringLiteral); !ok {
+			return nil, fmt.Errorf("object key must be *StringLiteral, got %T", k)
+		}
+
+		existing, ok := index[unescapedKey(k)]
+		if !ok {
+			out.Pairs[k] = override.Pairs[k]
+			out.Keys = append(out.Keys, k)
+			index[unescapedKey(k)] = k
+			continue
+		}
+
+		merged, err := m.merge(out.Pairs[existing], override.Pairs[k])
+		if err != nil {
+			return nil, err
+		}
+		out.Pairs[existing] = merged
+	}
+
+	return out, nil
+}
@@ -0,0 +1,115 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/nobletk/json-parser/internal/ast"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeAll(t *testing.T) {
+	tests := []struct {
+		name     string
+		inputs   []string
+		concat   bool
+		expected string
+	}{
+		{
+			name: "Three Way Scalar Override",
+			inputs: []string{
+				`{"a": 1, "b": "base", "c": true}`,
+				`{"b": "middle", "d": null}`,
+				`{"a": 3, "d": false}`,
+			},
+			expected: `{"a":3,"b":"middle","c":true,"d":false}`,
+		},
+		{
+			name: "Three Way Nested Objects",
+			inputs: []string{
+				`{"server": {"host": "localhost", "port": 80, "tls": {"enabled": false}}}`,
+				`{"server": {"port": 8080, "tls": {"cert": "a.pem"}}}`,
+				`{"server": {"tls": {"enabled": true}}, "debug": true}`,
+			},
+			expected: `{"server":{"host":"localhost","port":8080,"tls":{"enabled":true,"cert":"a.pem"}},"debug":true}`,
+		},
+		{
+			name: "Object Replaced By Scalar",
+			inputs: []string{
+				`{"a": {"b": 1}}`,
+				`{"a": 2}`,
+				`{"c": 3}`,
+			},
+			expected: `{"a":2,"c":3}`,
+		},
+		{
+			name: "Escaped Keys Match",
+			inputs: []string{
+				`{"a": {"b": 1}, "c\/d": 1}`,
+				`{"\u0061": {"\u0062": 2, "e": 3}, "c/d": 2}`,
+			},
+			expected: `{"a":{"b":2,"e":3},"c\/d":2}`,
+		},
+		{
+			name: "Arrays Replaced",
+			inputs: []string{
+				`{"list": [1, 2]}`,
+				`{"list": [3]}`,
+				`{"list": [4, 5]}`,
+			},
+			expected: `{"list":[4,5]}`,
+		},
+		{
+			name: "Arrays Concatenated",
+			inputs: []string{
+				`{"list": [1, 2]}`,
+				`{"list": [3]}`,
+				`{"list": [4, 5]}`,
+			},
+			concat:   true,
+			expected: `{"list":[1,2,3,4,5]}`,
+		},
+		{
+			name:     "Single Document",
+			inputs:   []string{`[1, 2]`},
+			expected: `[1,2]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docs := []ast.Element{}
+			for _, input := range tt.inputs {
+				docs = append(docs, parse(t, input))
+			}
+
+			m := &ast.Merger{ConcatArrays: tt.concat}
+			merged, err := m.MergeAll(docs...)
+			require.NoError(t, err)
+
+			out, err := ast.Marshal(merged)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(out))
+		})
+	}
+}
+
+func TestMergeAllLeavesInputsUntouched(t *testing.T) {
+	base := parse(t, `{"a": {"b": 1}, "list": [1]}`)
+	override := parse(t, `{"a": {"c": 2}, "list": [2]}`)
+
+	_, err := (&ast.Merger{ConcatArrays: true}).MergeAll(base, override)
+	require.NoError(t, err)
+
+	out, err := ast.Marshal(base)
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":{"b":1},"list":[1]}`, string(out))
+}
+
+func TestMergeAllErrors(t *testing.T) {
+	_, err := ast.MergeAll()
+	assert.EqualError(t, err, "no documents to merge")
+
+	_, err = ast.MergeAll(parse(t, `{}`), nil)
+	assert.EqualError(t, err, "cannot merge a nil document at index 1")
+}
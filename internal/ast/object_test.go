@@ -0,0 +1,66 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/nobletk/json-parser/internal/ast"
+	"github.com/nobletk/json-parser/internal/token"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObjectEach(t *testing.T) {
+	obj, ok := parse(t, `{"zeta": 1, "alpha": [true], "mid": {"x": null}, "beta": "b"}`).(*ast.Object)
+	require.True(t, ok, "root should be *ast.Object")
+
+	keys := []string{}
+	values := []string{}
+	obj.Each(func(key string, value ast.Element) bool {
+		keys = append(keys, key)
+		values = append(values, value.String())
+		return true
+	})
+	assert.Equal(t, []string{"zeta", "alpha", "mid", "beta"}, keys, "keys should follow source order")
+	assert.Equal(t, []string{"1", "[true]", "{\"x\":null}", "\"b\""}, values)
+
+	visited := []string{}
+	obj.Each(func(key string, value ast.Element) bool {
+		visited = append(visited, key)
+		return key != "alpha"
+	})
+	assert.Equal(t, []string{"zeta", "alpha"}, visited, "iteration should stop early")
+}
+
+func TestObjectStringStable(t *testing.T) {
+	parsed := parse(t, `{"zeta": 1, "alpha": {"y": [true, null], "x": "v"}, "mid": 2}`)
+
+	unordered := &ast.Object{Pairs: map[ast.Element]ast.Element{}}
+	for _, k := range []string{"zeta", "alpha", "mid", "beta"} {
+		unordered.Pairs[&ast.StringLiteral{Value: k}] = &ast.Null{Token: token.Token{Type: token.NULL, Literal: "null"}}
+	}
+
+	tests := []struct {
+		name     string
+		obj      ast.Element
+		expected string
+	}{
+		{
+			name:     "Parsed Order",
+			obj:      parsed,
+			expected: `{"zeta":1, "alpha":{"y":[true, null], "x":"v"}, "mid":2}`,
+		},
+		{
+			name:     "Sorted Without Key Order",
+			obj:      unordered,
+			expected: `{"alpha":null, "beta":null, "mid":null, "zeta":null}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 50; i++ {
+				assert.Equal(t, tt.expected, tt.obj.String(), "run %d", i)
+			}
+		})
+	}
+}
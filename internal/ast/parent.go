@@ -0,0 +1,70 @@
+package ast
+
+// Links holds the upward pointer of a node. It is only set by LinkParents,
+// e.g. when parsing with parser.WithParentLinks, since keeping it up to
+// date costs time and memory most users do not need.
+type Links struct {
+	// Parent is the object or array holding the node, or nil for the root.
+	// Object keys have the object as their parent too.
+	Parent Element
+}
+
+func (l *Links) links() *Links { return l }
+
+type linked interface {
+	links() *Links
+}
+
+// LinkParents sets the Parent of every node under root, replacing any
+// previous links. The Parent of root itself is cleared.
+func LinkParents(root Element) {
+	setParent(root, nil)
+	linkChildren(root)
+}
+
+func linkChildren(e Element) {
+	switch e := e.(type) {
+	case *Object:
+		for k, v := range e.Pairs {
+			setParent(k, e)
+			setParent(v, e)
+			linkChildren(v)
+		}
+	case *ArrayLiteral:
+		for _, el := range e.Elements {
+			setParent(el, e)
+			linkChildren(el)
+		}
+	}
+}
+
+func setParent(e, parent Element) {
+	if l, ok := e.(linked); ok {
+		l.links().Parent = parent
+	}
+}
+
+// Parent returns the object or array holding e, or nil when e is the root
+// or its links were never set.
+func Parent(e Element) Element {
+	if l, ok := e.(linked); ok {
+		return l.links().Parent
+	}
+	return nil
+}
+
+// KeyOf returns the key under which e is stored in its parent object. It
+// reports false when the parent is not an object or e is one of its keys.
+func KeyOf(e Element) (string, bool) {
+	obj, ok := Parent(e).(*Object)
+	if !ok {
+		return "", false
+	}
+
+	for _, k := range obj.orderedKeys() {
+		if obj.Pairs[k] == e {
+			return keyString(k), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,96 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/nobletk/json-parser/internal/ast"
+	"github.com/nobletk/json-parser/internal/lexer"
+	"github.com/nobletk/json-parser/internal/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const parentInput = `{
+	"key1": "value",
+	"key3": ["value", 1, true, null, {"inner": []}],
+	"key4": {"key5": {"key6": -0.5}}
+}`
+
+func parseLinked(t *testing.T, input string) ast.Element {
+	t.Helper()
+
+	jf, jErr := parser.New(lexer.New(nil, input), parser.WithParentLinks()).ParseFile()
+	require.Empty(t, jErr, "jsonErr should be empty")
+	return jf.Elements[0]
+}
+
+// assertLinked checks that every node below root points at the container
+// it was reached through.
+func assertLinked(t *testing.T, root ast.Element) {
+	t.Helper()
+
+	assert.Nil(t, ast.Parent(root), "root should have no parent")
+
+	count := 0
+	ast.Walk(root, func(path []string, e ast.Element) bool {
+		switch c := e.(type) {
+		case *ast.Object:
+			for k, v := range c.Pairs {
+				assert.Same(t, c, ast.Parent(k), "parent of key %v at %v", k, path)
+				assert.Same(t, c, ast.Parent(v), "parent of %v at %v", v, path)
+
+				key, ok := ast.KeyOf(v)
+				assert.True(t, ok)
+				assert.Equal(t, k.(*ast.StringLiteral).Value, key)
+				count++
+			}
+		case *ast.ArrayLiteral:
+			for _, el := range c.Elements {
+				assert.Same(t, c, ast.Parent(el), "parent of %v at %v", el, path)
+
+				_, ok := ast.KeyOf(el)
+				assert.False(t, ok, "array elements have no key")
+				count++
+			}
+		}
+		return true
+	})
+	assert.Equal(t, 11, count, "number of linked values")
+}
+
+func TestParentLinks(t *testing.T) {
+	root := parseLinked(t, parentInput)
+	assertLinked(t, root)
+
+	key6, err := ast.Select(root, "key4.key5.key6")
+	require.NoError(t, err)
+
+	key5 := ast.Parent(key6)
+	key, _ := ast.KeyOf(key5)
+	assert.Equal(t, "key5", key)
+	key, _ = ast.KeyOf(ast.Parent(key5))
+	assert.Equal(t, "key4", key)
+	assert.Same(t, root, ast.Parent(ast.Parent(key5)))
+}
+
+func TestParentLinksDisabled(t *testing.T) {
+	root := parse(t, parentInput)
+
+	ast.Walk(root, func(path []string, e ast.Element) bool {
+		assert.Nil(t, ast.Parent(e), "%v should not be linked", path)
+		return true
+	})
+}
+
+func TestParentLinksCopied(t *testing.T) {
+	root := parseLinked(t, parentInput)
+
+	thawed := ast.Thaw(ast.Freeze(root))
+	assertLinked(t, thawed)
+
+	key3, err := ast.Select(thawed, "key3")
+	require.NoError(t, err)
+	value, err := ast.Select(thawed, "key3[4]")
+	require.NoError(t, err)
+	assert.Same(t, key3, ast.Parent(value))
+}
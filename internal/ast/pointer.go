@@ -0,0 +1,180 @@
+package ast
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Get returns the element addressed by an RFC 6901 JSON Pointer such as
+// "/key3/1". The empty pointer addresses the root. Elements below a frozen
+// object or array are returned frozen.
+func (jf *JSONFile) Get(pointer string) (Element, error) {
+	segments, err := splitPointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	if len(jf.Elements) == 0 {
+		return nil, fmt.Errorf("cannot resolve %q in an empty document", pointer)
+	}
+	return resolvePointer(jf.Elements[0], segments)
+}
+
+// Set replaces the element addressed by pointer with value. A missing
+// object key is added after the existing ones, and the "-" segment appends
+// value to an array. Every segment but the last must already exist. Parent
+// links are not updated, so call LinkParents after editing if needed.
+func (jf *JSONFile) Set(pointer string, value Element) error {
+	segments, err := splitPointer(pointer)
+	if err != nil {
+		return err
+	}
+	if value == nil {
+		return fmt.Errorf("cannot set %q to a nil element", pointer)
+	}
+	if len(segments) == 0 {
+		jf.Elements = []Element{value}
+		return nil
+	}
+	if len(jf.Elements) == 0 {
+		return fmt.Errorf("cannot resolve %q in an empty document", pointer)
+	}
+
+	last := len(segments) - 1
+	parent, err := resolvePointer(jf.Elements[0], segments[:last])
+	if err != nil {
+		return err
+	}
+	at, seg := joinPointer(segments[:last]), segments[last]
+
+	switch e := parent.(type) {
+	case *Object:
+		if key, ok := pointerKey(e, seg); ok {
+			e.Pairs[key] = value
+			return nil
+		}
+		key, _ := FromInterface(seg)
+		e.Pairs[key] = value
+		e.Keys = append(e.Keys, key)
+	case *ArrayLiteral:
+		if seg == "-" {
+			e.Elements = append(e.Elements, value)
+			return nil
+		}
+		i, err := pointerIndex(seg, at, len(e.Elements))
+		if err != nil {
+			return err
+		}
+		e.Elements[i] = value
+	default:
+		return fmt.Errorf("cannot set %q in %T at %q", seg, parent, at)
+	}
+	return nil
+}
+
+// Delete removes the object key or array element addressed by pointer.
+// Later array elements move down by one. The root cannot be deleted.
+func (jf *JSONFile) Delete(pointer string) error {
+	segments, err := splitPointer(pointer)
+	if err != nil {
+		return err
+	}
+	if len(segments) == 0 {
+		return fmt.Errorf("cannot delete the root")
+	}
+	if len(jf.Elements) == 0 {
+		return fmt.Errorf("cannot resolve %q in an empty document", pointer)
+	}
+
+	last := len(segments) - 1
+	parent, err := resolvePointer(jf.Elements[0], segments[:last])
+	if err != nil {
+		return err
+	}
+	at, seg := joinPointer(segments[:last]), segments[last]
+
+	switch e := parent.(type) {
+	case *Object:
+		key, ok := pointerKey(e, seg)
+		if !ok {
+			return fmt.Errorf("key %q not found at %q", seg, at)
+		}
+		delete(e.Pairs, key)
+		for i, k := range e.Keys {
+			if k == key {
+				e.Keys = append(e.Keys[:i], e.Keys[i+1:]...)
+				break
+			}
+		}
+	case *ArrayLiteral:
+		i, err := pointerIndex(seg, at, len(e.Elements))
+		if err != nil {
+			return err
+		}
+		e.Elements = append(e.Elements[:i], e.Elements[i+1:]...)
+	default:
+		return fmt.Errorf("cannot delete %q from %T at %q", seg, parent, at)
+	}
+	return nil
+}
+
+// resolvePointer follows the decoded pointer segments down from root.
+func resolvePointer(root Element, segments []string) (Element, error) {
+	current := root
+	for i, seg := range segments {
+		at := joinPointer(segments[:i])
+
+		switch e := current.(type) {
+		case *Object:
+			key, ok := pointerKey(e, seg)
+			if !ok {
+				return nil, fmt.Errorf("key %q not found at %q", seg, at)
+			}
+			current = e.Pairs[key]
+		case *FrozenObject:
+			key, ok := pointerKey(e.obj, seg)
+			if !ok {
+				return nil, fmt.Errorf("key %q not found at %q", seg, at)
+			}
+			current = freeze(e.obj.Pairs[key])
+		case *ArrayLiteral:
+			index, err := pointerIndex(seg, at, len(e.Elements))
+			if err != nil {
+				return nil, err
+			}
+			current = e.Elements[index]
+		case *FrozenArray:
+			index, err := pointerIndex(seg, at, len(e.arr.Elements))
+			if err != nil {
+				return nil, err
+			}
+			current = freeze(e.arr.Elements[index])
+		default:
+			return nil, fmt.Errorf("cannot resolve %q in %T at %q", seg, current, at)
+		}
+	}
+	return current, nil
+}
+
+// pointerKey returns the key of o whose decoded text is key.
+func pointerKey(o *Object, key string) (Element, bool) {
+	for _, k := range o.orderedKeys() {
+		if unescapedKey(k) == key {
+			return k, true
+		}
+	}
+	return nil, false
+}
+
+// pointerIndex parses an array index segment, which RFC 6901 writes in
+// decimal without leading zeros, and checks it against length.
+func pointerIndex(seg, at string, length int) (int, error) {
+	index, err := strconv.Atoi(seg)
+	if err != nil || strings.Trim(seg, "0123456789") != "" || (len(seg) > 1 && seg[0] == '0') {
+		return 0, fmt.Errorf("invalid array index %q at %q", seg, at)
+	}
+	if index >= length {
+		return 0, fmt.Errorf("index %d out of range at %q (length %d)", index, at, length)
+	}
+	return index, nil
+}
@@ -0,0 +1,140 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/nobletk/json-parser/internal/ast"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGet(t *testing.T) {
+	doc := &ast.JSONFile{Elements: []ast.Element{
+		parse(t, `{"key": [1, {"a/b": true, "m~n": null}], "": "empty"}`),
+	}}
+
+	tests := []struct {
+		name        string
+		pointer     string
+		expected    string
+		expectedErr string
+	}{
+		{
+			name:     "Root",
+			pointer:  "",
+			expected: `{"key":[1, {"a/b":true, "m~n":null}], "":"empty"}`,
+		},
+		{
+			name:     "Array Element",
+			pointer:  "/key/0",
+			expected: "1",
+		},
+		{
+			name:     "Escaped Keys",
+			pointer:  "/key/1/a~1b",
+			expected: "true",
+		},
+		{
+			name:     "Tilde Key",
+			pointer:  "/key/1/m~0n",
+			expected: "null",
+		},
+		{
+			name:     "Empty Key",
+			pointer:  "/",
+			expected: `"empty"`,
+		},
+		{
+			name:        "Missing Key",
+			pointer:     "/nope",
+			expectedErr: `key "nope" not found at ""`,
+		},
+		{
+			name:        "Index Out Of Range",
+			pointer:     "/key/2",
+			expectedErr: `index 2 out of range at "/key" (length 2)`,
+		},
+		{
+			name:        "Leading Zero",
+			pointer:     "/key/01",
+			expectedErr: `invalid array index "01" at "/key"`,
+		},
+		{
+			name:        "Through Scalar",
+			pointer:     "/key/0/x",
+			expectedErr: `cannot resolve "x" in *ast.NumberLiteral at "/key/0"`,
+		},
+		{
+			name:        "Missing Slash",
+			pointer:     "key",
+			expectedErr: `invalid JSON Pointer "key": must start with '/'`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := doc.Get(tt.pointer)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, e.String())
+		})
+	}
+}
+
+func TestSet(t *testing.T) {
+	doc := &ast.JSONFile{Elements: []ast.Element{
+		parse(t, `{"server": {"port": 80, "hosts": ["a"]}}`),
+	}}
+
+	port, _ := ast.FromInterface(8080)
+	require.NoError(t, doc.Set("/server/port", port))
+
+	host, _ := ast.FromInterface("b")
+	require.NoError(t, doc.Set("/server/hosts/-", host))
+
+	debug, _ := ast.FromInterface(true)
+	require.NoError(t, doc.Set("/server/debug", debug))
+
+	first, _ := ast.FromInterface("c")
+	require.NoError(t, doc.Set("/server/hosts/0", first))
+
+	out, err := ast.Marshal(doc.Elements[0])
+	require.NoError(t, err)
+	assert.Equal(t, `{"server":{"port":8080,"hosts":["c","b"],"debug":true}}`, string(out))
+
+	assert.EqualError(t, doc.Set("/server/port/x", debug),
+		`cannot set "x" in *ast.NumberLiteral at "/server/port"`)
+	assert.EqualError(t, doc.Set("/missing/x", debug), `key "missing" not found at ""`)
+	assert.EqualError(t, doc.Set("/server/hosts/5", debug),
+		`index 5 out of range at "/server/hosts" (length 2)`)
+
+	require.NoError(t, doc.Set("", debug))
+	assert.Equal(t, "true", doc.String())
+}
+
+func TestDelete(t *testing.T) {
+	doc := &ast.JSONFile{Elements: []ast.Element{
+		parse(t, `{"a": 1, "b/c": [true, false, null], "d": {"e": "f"}}`),
+	}}
+
+	require.NoError(t, doc.Delete("/a"))
+	require.NoError(t, doc.Delete("/b~1c/1"))
+	require.NoError(t, doc.Delete("/d/e"))
+
+	out, err := ast.Marshal(doc.Elements[0])
+	require.NoError(t, err)
+	assert.Equal(t, `{"b/c":[true,null],"d":{}}`, string(out))
+
+	last, err := doc.Get("/b~1c/1")
+	require.NoError(t, err)
+	assert.Equal(t, "null", last.String())
+
+	assert.EqualError(t, doc.Delete("/a"), `key "a" not found at ""`)
+	assert.EqualError(t, doc.Delete("/b~1c/2"), `index 2 out of range at "/b~1c" (length 2)`)
+	assert.EqualError(t, doc.Delete("/d/e/f"), `key "e" not found at "/d"`)
+	assert.EqualError(t, doc.Delete("/b~1c/0/x"), `cannot delete "x" from *ast.Boolean at "/b~1c/0"`)
+	assert.EqualError(t, doc.Delete(""), "cannot delete the root")
+}
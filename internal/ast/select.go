@@ -0,0 +1,169 @@
+package ast
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type selector struct {
+	key     string
+	index   int
+	isIndex bool
+	// text is the segment as written, used in error messages.
+	text string
+}
+
+// Select looks up the element at path, written in dot and bracket
+// notation, e.g. "key3[1]" or "key4.key5". Keys that contain dots or
+// brackets can be quoted: `["a.b"]`. An empty path selects root. Frozen
+// objects and arrays are searched too, returning frozen elements.
+func Select(root Element, path string) (Element, error) {
+	selectors, err := parseSelectPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	current := root
+	for i, sel := range selectors {
+		at := selectPrefix(selectors[:i])
+
+		switch e := current.(type) {
+		case *Object:
+			if sel.isIndex {
+				return nil, fmt.Errorf("cannot index object at %q with %s", at, sel.text)
+			}
+			value, ok := objectValue(e, sel.key)
+			if !ok {
+				return nil, fmt.Errorf("key %q not found at %q", sel.key, at)
+			}
+			current = value
+		case *ArrayLiteral:
+			if !sel.isIndex {
+				return nil, fmt.Errorf("cannot select key %q from array at %q", sel.key, at)
+			}
+			if sel.index >= len(e.Elements) {
+				return nil, fmt.Errorf("index %d out of range at %q (length %d)", sel.index, at, len(e.Elements))
+			}
+			current = e.Elements[sel.index]
+		case *FrozenObject:
+			if sel.isIndex {
+				return nil, fmt.Errorf("cannot index object at %q with %s", at, sel.text)
+			}
+			value, ok := e.Get(sel.key)
+			if !ok {
+				return nil, fmt.Errorf("key %q not found at %q", sel.key, at)
+			}
+			current = value
+		case *FrozenArray:
+			if !sel.isIndex {
+				return nil, fmt.Errorf("cannot select key %q from array at %q", sel.key, at)
+			}
+			value, ok := e.Index(sel.index)
+			if !ok {
+				return nil, fmt.Errorf("index %d out of range at %q (length %d)", sel.index, at, e.Len())
+			}
+			current = value
+		default:
+			return nil, fmt.Errorf("cannot select %s from %T at %q", sel.text, current, at)
+		}
+	}
+
+	return current, nil
+}
+
+// objectValue returns the value of key in o, comparing it with the
+// unescaped text of every key.
+func objectValue(o *Object, key string) (Element, bool) {
+	for _, k := range o.orderedKeys() {
+		if unescapedKey(k) == key {
+			return o.Pairs[k], true
+		}
+	}
+	return nil, false
+}
+
+func selectPrefix(selectors []selector) string {
+	var out strings.Builder
+
+	for i, sel := range selectors {
+		if i > 0 && !strings.HasPrefix(sel.text, "[") {
+			out.WriteString(".")
+		}
+		out.WriteString(sel.text)
+	}
+
+	return out.String()
+}
+
+func parseSelectPath(path string) ([]selector, error) {
+	selectors := []selector{}
+
+	i := 0
+	for i < len(path) {
+		if path[i] == '[' {
+			sel, next, err := parseSelectBracket(path, i)
+			if err != nil {
+				return nil, err
+			}
+			selectors = append(selectors, sel)
+			i = next
+			continue
+		}
+
+		if len(selectors) > 0 {
+			if path[i] != '.' {
+				return nil, fmt.Errorf("expected '.' or '[' at offset %d in path %q", i, path)
+			}
+			i++
+		}
+
+		start := i
+		for i < len(path) && path[i] != '.' && path[i] != '[' {
+			i++
+		}
+		if i == start {
+			return nil, fmt.Errorf("empty key at offset %d in path %q", start, path)
+		}
+
+		key := path[start:i]
+		selectors = append(selectors, selector{key: key, text: key})
+	}
+
+	return selectors, nil
+}
+
+// parseSelectBracket parses the "[...]" segment starting at path[i] and
+// returns the offset just past the closing bracket.
+func parseSelectBracket(path string, i int) (selector, int, error) {
+	start := i
+	i++
+
+	if i < len(path) && path[i] == '"' {
+		var key strings.Builder
+		i++
+		for ; i < len(path) && path[i] != '"'; i++ {
+			if path[i] == '\\' && i+1 < len(path) {
+				i++
+			}
+			key.WriteByte(path[i])
+		}
+		if i+1 >= len(path) || path[i+1] != ']' {
+			return selector{}, 0, fmt.Errorf("unterminated quoted key at offset %d in path %q", start, path)
+		}
+		return selector{key: key.String(), text: path[start : i+2]}, i + 2, nil
+	}
+
+	end := strings.IndexByte(path[i:], ']')
+	if end < 0 {
+		return selector{}, 0, fmt.Errorf("missing ']' at offset %d in path %q", start, path)
+	}
+	text := path[i : i+end]
+
+	index, err := strconv.Atoi(text)
+	if err != nil || strings.Trim(text, "0123456789") != "" || (len(text) > 1 && text[0] == '0') {
+		return selector{}, 0, fmt.Errorf("invalid array index %q at offset %d in path %q", text, start, path)
+	}
+
+	return selector{index: index, isIndex: true, text: path[start : i+end+1]}, i + end + 1, nil
+}
@@ -0,0 +1,81 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/nobletk/json-parser/internal/ast"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelect(t *testing.T) {
+	input := `{
+		"key1": "value",
+		"key3": ["value", 1, [true, {"deep": null}]],
+		"key4": {
+			"key4": -0.2e2,
+			"key5": [false, {"key6": "nested"}]
+		},
+		"a.b": {"[c]": 7},
+		"a\"b": 1,
+		"c\/d": 2
+	}`
+	root := parse(t, input)
+
+	tests := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{name: "Top Level Key", path: "key1", expected: `"value"`},
+		{name: "Array Index", path: "key3[1]", expected: "1"},
+		{name: "Nested Arrays", path: "key3[2][1].deep", expected: "null"},
+		{name: "Nested Objects", path: "key4.key4", expected: "-0.2e2"},
+		{name: "Object In Array", path: "key4.key5[1].key6", expected: `"nested"`},
+		{name: "Quoted Key", path: `["a.b"]["[c]"]`, expected: "7"},
+		{name: "Quoted Key After Dot Path", path: `key4["key5"][0]`, expected: "false"},
+		{name: "Escaped Quote In Key", path: `["a\"b"]`, expected: "1"},
+		{name: "Escaped Solidus In Key", path: `["c/d"]`, expected: "2"},
+	}
+
+	e, err := ast.Select(root, "")
+	require.NoError(t, err)
+	assert.Same(t, root, e, "Empty path should select the root")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := ast.Select(root, tt.path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, e.String())
+		})
+	}
+}
+
+func TestSelectErrors(t *testing.T) {
+	root := parse(t, `{"key1": "value", "key3": ["value", 1], "key4": {"key5": null}}`)
+
+	tests := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{name: "Missing Key", path: "key4.missing", expected: `key "missing" not found at "key4"`},
+		{name: "Index Out Of Range", path: "key3[2]", expected: `index 2 out of range at "key3" (length 2)`},
+		{name: "Key On Array", path: "key3.first", expected: `cannot select key "first" from array at "key3"`},
+		{name: "Index On Object", path: "key4[0]", expected: `cannot index object at "key4" with [0]`},
+		{name: "Select From Scalar", path: "key1.x", expected: `cannot select x from *ast.StringLiteral at "key1"`},
+		{name: "Empty Key", path: "key4..key5", expected: `empty key at offset 5 in path "key4..key5"`},
+		{name: "Trailing Dot", path: "key4.", expected: `empty key at offset 5 in path "key4."`},
+		{name: "Missing Dot", path: "key3[0]x", expected: `expected '.' or '[' at offset 7 in path "key3[0]x"`},
+		{name: "Invalid Index", path: "key3[+1]", expected: `invalid array index "+1" at offset 4 in path "key3[+1]"`},
+		{name: "Missing Bracket", path: "key3[1", expected: `missing ']' at offset 4 in path "key3[1"`},
+		{name: "Unterminated Quote", path: `["key1`, expected: `unterminated quoted key at offset 0 in path "[\"key1"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ast.Select(root, tt.path)
+			assert.EqualError(t, err, tt.expected)
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package ast
+
+type DocStats struct {
+	Objects  int
+	Arrays   int
+	Strings  int
+	Numbers  int
+	Booleans int
+	Nulls    int
+	Keys     int
+	MaxDepth int
+}
+
+// Stats counts the nodes of e by type. MaxDepth is the deepest nesting of
+// objects and arrays, so a scalar has a depth of 0 and `[{}]` a depth of 2.
+// Object keys are counted in Keys rather than Strings. Frozen objects and
+// arrays are counted like the ones they were frozen from.
+func Stats(e Element) DocStats {
+	var stats DocStats
+
+	Walk(e, func(path []string, e Element) bool {
+		switch e := unwrap(e).(type) {
+		case *Object:
+			stats.Objects++
+			stats.Keys += len(e.Pairs)
+			stats.MaxDepth = max(stats.MaxDepth, len(path)+1)
+		case *ArrayLiteral:
+			stats.Arrays++
+			stats.MaxDepth = max(stats.MaxDepth, len(path)+1)
+		case *StringLiteral:
+			stats.Strings++
+		case *NumberLiteral:
+			stats.Numbers++
+		case *Boolean:
+			stats.Booleans++
+		case *Null:
+			stats.Nulls++
+		}
+		return true
+	})
+
+	return stats
+}
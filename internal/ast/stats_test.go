@@ -0,0 +1,69 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/nobletk/json-parser/internal/ast"
+	"github.com/nobletk/json-parser/internal/lexer"
+	"github.com/nobletk/json-parser/internal/parser"
+	"github.com/nobletk/json-parser/pkg/mylog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStats(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected ast.DocStats
+	}{
+		{
+			name:     "Empty Object",
+			input:    `{}`,
+			expected: ast.DocStats{Objects: 1, MaxDepth: 1},
+		},
+		{
+			name: "Mixed Document",
+			input: `{
+	       "key1": "value",
+	       "key2": -123,
+	       "key3": ["value", 1, true, null, -0.2e2],
+	       "key4": {
+	               "key4": null,
+	               "key5": [false, [{}]]
+	       }
+	   }`,
+			expected: ast.DocStats{
+				Objects:  3,
+				Arrays:   3,
+				Strings:  2,
+				Numbers:  3,
+				Booleans: 2,
+				Nulls:    2,
+				Keys:     6,
+				MaxDepth: 5,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := parse(t, tt.input)
+
+			assert.Equal(t, tt.expected, ast.Stats(root))
+		})
+	}
+}
+
+func parse(t *testing.T, input string) ast.Element {
+	t.Helper()
+
+	log := mylog.CreateLogger(false)
+	l := lexer.New(log, input)
+	p := parser.New(l)
+	jf, jsonErr := p.ParseFile()
+	require.Empty(t, jsonErr, "jsonErr should be empty")
+	require.Len(t, jf.Elements, 1, "length of elements isn't correct")
+
+	return jf.Elements[0]
+}
@@ -0,0 +1,105 @@
+package ast
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Trivia holds the source text around a node that belongs to no token,
+// i.e. whitespace and comments. It is only recorded when parsing with
+// parser.WithTrivia, and Marshaler.PreserveTrivia writes it back to
+// reproduce the input byte for byte.
+type Trivia struct {
+	// Before precedes the first token of the node.
+	Before string
+	// After follows the last token of the node up to the ':' or ',' after
+	// it. For the root it runs to the end of the input.
+	After string
+	// Closing precedes the '}' or ']' of an object or array.
+	Closing string
+}
+
+func (t *Trivia) trivia() *Trivia { return t }
+
+type withTrivia interface {
+	trivia() *Trivia
+}
+
+// TriviaOf returns the trivia of e so it can be read or changed, or nil
+// when e cannot hold any.
+func TriviaOf(e Element) *Trivia {
+	if t, ok := e.(withTrivia); ok {
+		return t.trivia()
+	}
+	return nil
+}
+
+// writeWithTrivia writes e surrounded by its trivia in place of the usual
+// indentation. Comments are part of the trivia, so they are not written
+// separately.
+func (m *Marshaler) writeWithTrivia(out *bytes.Buffer, e Element) error {
+	switch f := e.(type) {
+	case *FrozenObject:
+		e = f.obj
+	case *FrozenArray:
+		e = f.arr
+	}
+
+	t := TriviaOf(e)
+	if t == nil {
+		t = &Trivia{}
+	}
+	out.WriteString(t.Before)
+
+	switch e := e.(type) {
+	case *Object:
+		out.WriteString("{")
+		keys := e.orderedKeys()
+		for i, key := range keys {
+			if _, ok := key.(*StringLiteral); !ok {
+				return fmt.Errorf("object key must be *StringLiteral, got %T", key)
+			}
+			if err := m.writeWithTrivia(out, key); err != nil {
+				return err
+			}
+			out.WriteString(":")
+			if err := m.writeWithTrivia(out, e.Pairs[key]); err != nil {
+				return err
+			}
+			if i < len(keys)-1 {
+				out.WriteString(",")
+			}
+		}
+		out.WriteString(t.Closing + "}")
+	case *ArrayLiteral:
+		out.WriteString("[")
+		for i, el := range e.Elements {
+			if err := m.writeWithTrivia(out, el); err != nil {
+				return err
+			}
+			if i < len(e.Elements)-1 {
+				out.WriteString(",")
+			}
+		}
+		out.WriteString(t.Closing + "]")
+	case *StringLiteral:
+		if err := m.writeString(out, e); err != nil {
+			return err
+		}
+	case *NumberLiteral, *Boolean, *Null:
+		// The original literal is kept as it is, even where writeElement
+		// would rewrite it, e.g. for hexadecimal numbers or undefined.
+		if literal := e.TokenLiteral(); literal != "" {
+			out.WriteString(literal)
+		} else if err := m.writeElement(out, e, 0); err != nil {
+			return err
+		}
+	default:
+		if err := m.writeElement(out, e, 0); err != nil {
+			return err
+		}
+	}
+
+	out.WriteString(t.After)
+	return nil
+}
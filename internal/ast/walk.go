@@ -0,0 +1,72 @@
+package ast
+
+import "strconv"
+
+// Walk traverses e depth-first and calls fn for every node together with
+// the object keys and array indexes leading to it. Object members are
+// visited in their parsed order. The children of a frozen object or array
+// are visited frozen as well. Returning false from fn skips the children of
+// that node.
+func Walk(e Element, fn func(path []string, e Element) bool) {
+	walk(e, []string{}, fn)
+}
+
+func walk(e Element, path []string, fn func(path []string, e Element) bool) {
+	if !fn(path, e) {
+		return
+	}
+
+	switch e := e.(type) {
+	case *Object:
+		for _, k := range e.orderedKeys() {
+			walk(e.Pairs[k], append(path[:len(path):len(path)], keyString(k)), fn)
+		}
+	case *ArrayLiteral:
+		for i, el := range e.Elements {
+			walk(el, append(path[:len(path):len(path)], strconv.Itoa(i)), fn)
+		}
+	case *FrozenObject:
+		for _, k := range e.obj.orderedKeys() {
+			walk(freeze(e.obj.Pairs[k]), append(path[:len(path):len(path)], keyString(k)), fn)
+		}
+	case *FrozenArray:
+		for i, el := range e.arr.Elements {
+			walk(freeze(el), append(path[:len(path):len(path)], strconv.Itoa(i)), fn)
+		}
+	}
+}
+
+// Transform returns a rewritten copy of root, leaving root unchanged. fn is
+// called depth-first like in Walk with every node of the copy and returns
+// the node to put in its place, which may be the node itself, and whether
+// to continue into the children of the returned node. Nodes returned by fn
+// become part of the result as they are.
+func Transform(root Element, fn func(path []string, e Element) (Element, bool)) Element {
+	return transform(deepCopy(root), []string{}, fn)
+}
+
+func transform(e Element, path []string, fn func(path []string, e Element) (Element, bool)) Element {
+	e, descend := fn(path, e)
+	if !descend {
+		return e
+	}
+
+	switch e := e.(type) {
+	case *Object:
+		for _, k := range e.orderedKeys() {
+			e.Pairs[k] = transform(e.Pairs[k], append(path[:len(path):len(path)], keyString(k)), fn)
+		}
+	case *ArrayLiteral:
+		for i, el := range e.Elements {
+			e.Elements[i] = transform(el, append(path[:len(path):len(path)], strconv.Itoa(i)), fn)
+		}
+	}
+	return e
+}
+
+func keyString(k Element) string {
+	if sl, ok := k.(*StringLiteral); ok {
+		return sl.Value
+	}
+	return k.String()
+}
@@ -0,0 +1,47 @@
+package ast_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nobletk/json-parser/internal/ast"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransformRedact(t *testing.T) {
+	input := `{"user": "Ann", "password": "hunter2", "db": [{"password": {"old": "x"}, "port": 5432}]}`
+	root := parse(t, input)
+
+	redacted := ast.Transform(root, func(path []string, e ast.Element) (ast.Element, bool) {
+		if len(path) > 0 && path[len(path)-1] == "password" {
+			value, _ := ast.FromInterface("***")
+			return value, false
+		}
+		return e, true
+	})
+
+	out, err := ast.Marshal(redacted)
+	require.NoError(t, err)
+	assert.Equal(t, `{"user":"Ann","password":"***","db":[{"password":"***","port":5432}]}`, string(out))
+
+	original, err := ast.Marshal(root)
+	require.NoError(t, err)
+	assert.Equal(t, `{"user":"Ann","password":"hunter2","db":[{"password":{"old":"x"},"port":5432}]}`, string(original),
+		"the input tree should be unchanged")
+}
+
+func TestTransformLowercase(t *testing.T) {
+	root := ast.Freeze(parse(t, `["A", {"Key": "MiXed"}, 1]`))
+
+	lowered := ast.Transform(root, func(path []string, e ast.Element) (ast.Element, bool) {
+		if sl, ok := e.(*ast.StringLiteral); ok {
+			sl.Value = strings.ToLower(sl.Value)
+		}
+		return e, true
+	})
+
+	out, err := ast.Marshal(lowered)
+	require.NoError(t, err)
+	assert.Equal(t, `["a",{"Key":"mixed"},1]`, string(out), "keys are not visited")
+}
@@ -0,0 +1,126 @@
+package ast
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// errNotInline stops writeInline at a node that cannot be written on one
+// line, or once the line is wider than allowed.
+var errNotInline = errors.New("element cannot be written inline")
+
+// inlineBuffer collects an element written on one line and keeps track of
+// its width, so writing stops as soon as it no longer fits.
+type inlineBuffer struct {
+	bytes.Buffer
+	// room is the width left on the line.
+	room int
+	// width is the width in runes of the first counted bytes.
+	width   int
+	counted int
+}
+
+// fits reports whether what was written so far still fits in room.
+func (b *inlineBuffer) fits() bool {
+	b.width += utf8.RuneCount(b.Bytes()[b.counted:])
+	b.counted = b.Len()
+	return b.width <= b.room
+}
+
+// writeValue writes e like writeElement, except that an object or array is
+// kept on one line when MaxWidth allows it. comma tells whether a comma
+// follows e on its line.
+func (m *Marshaler) writeValue(out *bytes.Buffer, e Element, depth int, comma bool) error {
+	if m.MaxWidth <= 0 || m.Indent == "" || !isContainer(e) {
+		return m.writeElement(out, e, depth)
+	}
+
+	buf := &inlineBuffer{room: m.MaxWidth - lineWidth(out)}
+	if comma {
+		buf.room--
+	}
+
+	err := m.writeInline(buf, e)
+	if err == errNotInline {
+		return m.writeElement(out, e, depth)
+	}
+	if err != nil {
+		return err
+	}
+
+	out.Write(buf.Bytes())
+	return nil
+}
+
+// writeInline writes e on a single line, with a space after every comma
+// and colon. It returns errNotInline when e holds comments that would have
+// to be written or does not fit in the room left in buf.
+func (m *Marshaler) writeInline(buf *inlineBuffer, e Element) error {
+	switch e := e.(type) {
+	case *FrozenObject:
+		return m.writeInline(buf, e.obj)
+	case *FrozenArray:
+		return m.writeInline(buf, e.arr)
+	case *Object:
+		buf.WriteString("{")
+		for i, key := range e.orderedKeys() {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			if _, ok := key.(*StringLiteral); !ok {
+				return fmt.Errorf("object key must be *StringLiteral, got %T", key)
+			}
+			if err := m.writeInlineChild(buf, key); err != nil {
+				return err
+			}
+			buf.WriteString(": ")
+			if err := m.writeInlineChild(buf, e.Pairs[key]); err != nil {
+				return err
+			}
+		}
+		buf.WriteString("}")
+	case *ArrayLiteral:
+		buf.WriteString("[")
+		for i, el := range e.Elements {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			if err := m.writeInlineChild(buf, el); err != nil {
+				return err
+			}
+		}
+		buf.WriteString("]")
+	default:
+		if err := m.writeElement(&buf.Buffer, e, 0); err != nil {
+			return err
+		}
+	}
+
+	if !buf.fits() {
+		return errNotInline
+	}
+	return nil
+}
+
+func (m *Marshaler) writeInlineChild(buf *inlineBuffer, e Element) error {
+	if !m.OmitComments && (len(LeadingComments(e)) > 0 || len(TrailingComments(e)) > 0) {
+		return errNotInline
+	}
+	return m.writeInline(buf, e)
+}
+
+func isContainer(e Element) bool {
+	switch e.(type) {
+	case *Object, *ArrayLiteral, *FrozenObject, *FrozenArray:
+		return true
+	}
+	return false
+}
+
+// lineWidth returns the width in runes of the last line of out.
+func lineWidth(out *bytes.Buffer) int {
+	b := out.Bytes()
+	return utf8.RuneCount(b[bytes.LastIndexByte(b, '\n')+1:])
+}
@@ -0,0 +1,140 @@
+package ast
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// XMLMarshaler converts a document to XML for consumers that cannot read
+// JSON. Every value becomes an element: object keys name the elements of
+// their values, arrays repeat the element once per item and scalars are
+// written as text content, with null as an empty element. Items of an
+// array that is not the value of a key are named "item".
+type XMLMarshaler struct {
+	// SanitizeNames replaces the characters of keys that are not allowed
+	// in XML names with '_', and prefixes keys that cannot start a name
+	// with '_'. Otherwise such keys are an error.
+	SanitizeNames bool
+}
+
+// ToXML converts the root of jf to XML, wrapped in an element named
+// rootName, e.g. {"a": [1, 2]} becomes <root><a>1</a><a>2</a></root> and
+// [1, 2] becomes <root><item>1</item><item>2</item></root>.
+func (jf *JSONFile) ToXML(rootName string) ([]byte, error) {
+	m := &XMLMarshaler{}
+	return m.Marshal(jf, rootName)
+}
+
+func (m *XMLMarshaler) Marshal(jf *JSONFile, rootName string) ([]byte, error) {
+	if len(jf.Elements) == 0 {
+		return nil, fmt.Errorf("cannot convert an empty document to XML")
+	}
+
+	name, err := m.xmlName(rootName)
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	if err := m.writeItem(&out, name, Thaw(jf.Elements[0])); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// writeElement writes e as one element named name, or as one per item when
+// e is an array.
+func (m *XMLMarshaler) writeElement(out *bytes.Buffer, name string, e Element) error {
+	name, err := m.xmlName(name)
+	if err != nil {
+		return err
+	}
+
+	if arr, ok := e.(*ArrayLiteral); ok {
+		for _, el := range arr.Elements {
+			if err := m.writeItem(out, name, Thaw(el)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return m.writeItem(out, name, e)
+}
+
+func (m *XMLMarshaler) writeItem(out *bytes.Buffer, name string, e Element) error {
+	out.WriteString("<" + name + ">")
+
+	switch e := e.(type) {
+	case *Object:
+		for _, k := range e.orderedKeys() {
+			if err := m.writeElement(out, unescapedKey(k), Thaw(e.Pairs[k])); err != nil {
+				return err
+			}
+		}
+	case *ArrayLiteral:
+		if err := m.writeElement(out, "item", e); err != nil {
+			return err
+		}
+	case *StringLiteral:
+		str, err := e.Unescaped()
+		if err != nil {
+			return err
+		}
+		if err := xml.EscapeText(out, []byte(str)); err != nil {
+			return err
+		}
+	case *NumberLiteral:
+		out.WriteString(strings.TrimPrefix(e.Token.Literal, "+"))
+	case *Boolean:
+		out.WriteString(fmt.Sprintf("%t", e.Value))
+	case *Null:
+	default:
+		return fmt.Errorf("cannot convert %T to XML", e)
+	}
+
+	out.WriteString("</" + name + ">")
+	return nil
+}
+
+// xmlName checks that name can be used as an XML element name, or makes
+// it one with SanitizeNames.
+func (m *XMLMarshaler) xmlName(name string) (string, error) {
+	valid := name != ""
+	for i, r := range name {
+		if !isXMLNameChar(r, i == 0) {
+			valid = false
+			break
+		}
+	}
+	if valid {
+		return name, nil
+	}
+	if !m.SanitizeNames {
+		return "", fmt.Errorf("key %q is not a valid XML element name", name)
+	}
+
+	var out strings.Builder
+	if r, _ := utf8.DecodeRuneInString(name); name == "" || !isXMLNameChar(r, true) {
+		out.WriteByte('_')
+	}
+	for _, r := range name {
+		if !isXMLNameChar(r, false) {
+			r = '_'
+		}
+		out.WriteRune(r)
+	}
+	return out.String(), nil
+}
+
+// isXMLNameChar reports whether r may appear in an XML name, at its start
+// when first is set. Colons are left out since they separate namespaces.
+func isXMLNameChar(r rune, first bool) bool {
+	if unicode.IsLetter(r) || r == '_' {
+		return true
+	}
+	return !first && (unicode.IsDigit(r) || r == '-' || r == '.')
+}
@@ -0,0 +1,67 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/nobletk/json-parser/internal/ast"
+	"github.com/nobletk/json-parser/internal/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToXML(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:  "Nested Object",
+			input: `{"name": "a <b> & \"c\"", "owner": {"id": 7, "active": true, "manager": null}}`,
+			expected: `<root><name>a &lt;b&gt; &amp; &#34;c&#34;</name>` +
+				`<owner><id>7</id><active>true</active><manager></manager></owner></root>`,
+		},
+		{
+			name:     "Array Of Scalars",
+			input:    `{"tags": ["x", 1.5, false]}`,
+			expected: `<root><tags>x</tags><tags>1.5</tags><tags>false</tags></root>`,
+		},
+		{
+			name:     "Root Array",
+			input:    `[{"id": 1}, [2, 3]]`,
+			expected: `<root><item><id>1</id></item><item><item>2</item><item>3</item></item></root>`,
+		},
+		{
+			name:     "Empty Values",
+			input:    `{"obj": {}, "arr": []}`,
+			expected: `<root><obj></obj></root>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jf, jErr := parser.ParseString(tt.input)
+			require.Nil(t, jErr)
+
+			out, err := jf.ToXML("root")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(out))
+		})
+	}
+}
+
+func TestToXMLInvalidNames(t *testing.T) {
+	jf, jErr := parser.ParseString(`{"first name": 1, "2nd": 2, "ok-key": 3}`)
+	require.Nil(t, jErr)
+
+	_, err := jf.ToXML("root")
+	assert.EqualError(t, err, `key "first name" is not a valid XML element name`)
+
+	_, err = jf.ToXML("")
+	assert.EqualError(t, err, `key "" is not a valid XML element name`)
+
+	m := &ast.XMLMarshaler{SanitizeNames: true}
+	out, err := m.Marshal(jf, "root")
+	require.NoError(t, err)
+	assert.Equal(t, `<root><first_name>1</first_name><_2nd>2</_2nd><ok-key>3</ok-key></root>`, string(out))
+}
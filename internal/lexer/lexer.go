@@ -1,12 +1,22 @@
 package lexer
 
 import (
+	"context"
+	"encoding/binary"
+	"fmt"
 	"log/slog"
-	"regexp"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
 
 	"github.com/nobletk/json-parser/internal/token"
 )
 
+type LexError struct {
+	Msg string
+	Pos token.Position
+}
+
 type Lexer struct {
 	input        string
 	position     int
@@ -14,21 +24,242 @@ type Lexer struct {
 	ch           byte
 	line         int
 	column       int
+	start        token.Position
+	tokenStart   int
 	Logger       *slog.Logger
+
+	comments        bool
+	maxStringLength int
+	maxNumberDigits int
+	hexNumbers      bool
+	leadingPlus     bool
+	rawStrings      bool
+	tabWidth        int
+}
+
+type Option func(*Lexer)
+
+// WithComments makes the lexer emit COMMENT tokens for '//' line comments
+// and '/* */' block comments instead of treating them as illegal.
+func WithComments() Option {
+	return func(l *Lexer) {
+		l.comments = true
+	}
+}
+
+// WithMaxStringLength rejects strings whose content is longer than n bytes
+// with an ILLEGAL token. A limit of zero or less disables the check.
+func WithMaxStringLength(n int) Option {
+	return func(l *Lexer) {
+		l.maxStringLength = n
+	}
+}
+
+// WithMaxNumberDigits rejects numbers with more than n digits before or
+// after the 'e' with an ILLEGAL token. Its literal stops at the digit that
+// passed the limit, so a pathological literal such as 1e999999999... is
+// not copied, and the rest of the number is skipped. A limit of zero or
+// less disables the check.
+func WithMaxNumberDigits(n int) Option {
+	return func(l *Lexer) {
+		l.maxNumberDigits = n
+	}
+}
+
+// AllowHexNumbers makes the lexer accept JSON5 hexadecimal integers such
+// as 0xFF and -0x1F as NUMBER tokens.
+func AllowHexNumbers() Option {
+	return func(l *Lexer) {
+		l.hexNumbers = true
+	}
+}
+
+// AllowLeadingPlus makes the lexer accept numbers with an explicit positive
+// sign such as +123 and +1.5e2 as NUMBER tokens.
+func AllowLeadingPlus() Option {
+	return func(l *Lexer) {
+		l.leadingPlus = true
+	}
+}
+
+// WithRawStrings makes the lexer set RawLiteral on STRING tokens to their
+// exact source, quotes included, for tools that re-emit strings unchanged.
+func WithRawStrings() Option {
+	return func(l *Lexer) {
+		l.rawStrings = true
+	}
 }
 
-func New(logger *slog.Logger, input string) *Lexer {
+// WithTabWidth makes a tab advance the column by n instead of 1, so
+// reported positions match editors that render tabs n columns wide.
+func WithTabWidth(n int) Option {
+	return func(l *Lexer) {
+		l.tabWidth = n
+	}
+}
+
+// discardHandler drops every record. Its Enabled method returns false, so
+// the logging calls return before formatting anything.
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
+
+// New creates a lexer for input. Input that starts with a UTF-16 byte order
+// mark is transcoded to UTF-8 first, so positions and offsets refer to the
+// decoded text. A nil logger disables logging, which is much cheaper than
+// a logger that filters everything out by level.
+func New(logger *slog.Logger, input string, opts ...Option) *Lexer {
+	return NewAt(logger, input, token.Position{Line: 1, Column: 1}, opts...)
+}
+
+// NewAt creates a lexer for input that was taken from a larger document,
+// where start is the position of the first character of input in that
+// document. Reported positions then map back to the host document, while
+// Offset stays relative to input.
+func NewAt(logger *slog.Logger, input string, start token.Position, opts ...Option) *Lexer {
+	if logger == nil {
+		logger = slog.New(discardHandler{})
+	}
+
 	l := &Lexer{
-		input:  input,
+		input:  decodeUTF16(input),
 		Logger: logger,
-		line:   1,
-		column: 0,
+		start:  start,
+		line:   start.Line,
+		column: start.Column - 1,
+	}
+
+	for _, opt := range opts {
+		opt(l)
 	}
+
 	l.readChar()
 
 	return l
 }
 
+// logging reports whether the logger writes Info records. Log calls check
+// it first so their attributes are not built when logging is off.
+func (l *Lexer) logging() bool {
+	return l.Logger.Enabled(context.Background(), slog.LevelInfo)
+}
+
+// Reset makes l read input from the start, keeping its options and the
+// starting position given to NewAt, so one lexer can be reused for many
+// documents.
+func (l *Lexer) Reset(input string) {
+	l.input = decodeUTF16(input)
+	l.position = 0
+	l.readPosition = 0
+	l.tokenStart = 0
+	l.ch = 0
+	l.line = l.start.Line
+	l.column = l.start.Column - 1
+	l.readChar()
+}
+
+// decodeUTF16 converts input to UTF-8 when it starts with a UTF-16LE or
+// UTF-16BE byte order mark and returns it unchanged otherwise. A trailing
+// odd byte is decoded as U+FFFD.
+func decodeUTF16(input string) string {
+	var order binary.ByteOrder
+	switch {
+	case strings.HasPrefix(input, "\xff\xfe"):
+		order = binary.LittleEndian
+	case strings.HasPrefix(input, "\xfe\xff"):
+		order = binary.BigEndian
+	default:
+		return input
+	}
+
+	data := input[2:]
+	units := make([]uint16, 0, len(data)/2+1)
+	for i := 0; i+1 < len(data); i += 2 {
+		units = append(units, order.Uint16([]byte(data[i:i+2])))
+	}
+	if len(data)%2 != 0 {
+		units = append(units, utf8.RuneError)
+	}
+
+	return string(utf16.Decode(units))
+}
+
+// SkipLines advances past the leading lines of the remaining input for
+// which skip returns true, e.g. a header in front of the JSON. Lines are
+// passed without their line ending. Positions of later tokens still count
+// the skipped lines. It returns the number of lines skipped.
+func (l *Lexer) SkipLines(skip func(line string) bool) int {
+	skipped := 0
+
+	for l.ch != 0 {
+		line := l.input[l.position:]
+		if i := strings.IndexByte(line, '\n'); i >= 0 {
+			line = line[:i]
+		}
+		if !skip(strings.TrimSuffix(line, "\r")) {
+			break
+		}
+
+		for l.ch != '\n' && l.ch != 0 {
+			l.readChar()
+		}
+		l.readChar()
+		skipped++
+	}
+
+	return skipped
+}
+
+// Offset returns the byte offset of the character the lexer is currently
+// looking at. Once the input is exhausted it equals Len.
+func (l *Lexer) Offset() int {
+	return l.position
+}
+
+// TokenOffset returns the byte offset at which the token last returned by
+// NextToken starts.
+func (l *Lexer) TokenOffset() int {
+	return l.tokenStart
+}
+
+// Slice returns the input between the byte offsets start and end.
+func (l *Lexer) Slice(start, end int) string {
+	return l.input[start:end]
+}
+
+// Len returns the length of the input in bytes.
+func (l *Lexer) Len() int {
+	return len(l.input)
+}
+
+func Tokenize(logger *slog.Logger, input string) ([]token.Token, *LexError) {
+	l := New(logger, input)
+	tokens := []token.Token{}
+
+	var lexErr *LexError
+	for {
+		tok := l.NextToken()
+		tokens = append(tokens, tok)
+
+		if tok.Type == token.ILLEGAL && lexErr == nil {
+			msg := fmt.Sprintf("Illegal token '%s'\n", tok.Literal)
+			if tok.Reason != "" {
+				msg = fmt.Sprintf("%s\n", tok.Reason)
+			}
+			lexErr = &LexError{Msg: msg, Pos: tok.Position}
+		}
+
+		if tok.Type == token.EOF {
+			break
+		}
+	}
+
+	return tokens, lexErr
+}
+
 func newToken(tokenType token.TokenType, ch byte, pos token.Position) token.Token {
 	return token.Token{Type: tokenType, Literal: string(ch), Position: pos}
 }
@@ -38,6 +269,7 @@ func (l *Lexer) NextToken() token.Token {
 
 	l.skipWhitespace()
 	pos := token.Position{Line: l.line, Column: l.column}
+	l.tokenStart = l.position
 
 	switch l.ch {
 	case '{':
@@ -54,21 +286,41 @@ func (l *Lexer) NextToken() token.Token {
 		tok = newToken(token.COLON, l.ch, pos)
 	case '"':
 		tok = l.readString()
+	case '/':
+		switch {
+		case l.comments && (l.peekChar() == '/' || l.peekChar() == '*'):
+			tok = l.readComment()
+		case l.peekChar() == '/' || l.peekChar() == '*':
+			tok = newToken(token.ILLEGAL, l.ch, pos)
+			tok.Reason = "Comments are not allowed in JSON; did you mean to enable comments?"
+		default:
+			tok = newToken(token.ILLEGAL, l.ch, pos)
+		}
+	case '\'':
+		tok = newToken(token.ILLEGAL, l.ch, pos)
+		tok.Reason = l.singleQuoteHint()
 	case 0:
 		tok.Literal = ""
 		tok.Type = token.EOF
 		tok.Position = pos
 	default:
 		if l.isLetter(l.ch) {
-			l.Logger.Info("NextToken isLetter default:")
+			if l.logging() {
+				l.Logger.Info("NextToken isLetter default:")
+			}
 			tok.Literal = l.readIdentifier()
 			tok.Type = token.LookupIdent(tok.Literal)
 			tok.Position = pos
+			if tok.Type == token.ILLEGAL {
+				tok.Reason = identifierHint(tok.Literal)
+			}
 			return tok
 		}
 
-		if l.ch == '-' || l.isDigit(l.ch) {
-			l.Logger.Info("NextToken isDigit default:")
+		if l.ch == '-' || l.isDigit(l.ch) || (l.leadingPlus && l.ch == '+') {
+			if l.logging() {
+				l.Logger.Info("NextToken isDigit default:")
+			}
 			tok = l.readNumber()
 			return tok
 		}
@@ -84,26 +336,33 @@ func (l *Lexer) NextToken() token.Token {
 func (l *Lexer) readString() token.Token {
 	startPos := token.Position{Line: l.line, Column: l.column}
 	start := l.position + 1
-	l.Logger.Info("Reading String Start:",
-		"curChar", string(l.ch),
-		"curPosition", l.position,
-		"peekChar", string(l.peekChar()),
-		"peekCharPosition", l.position+1,
-	)
-
-ReadLoop:
-	for {
-		l.readChar()
-		prvCh := l.input[l.position-1]
-
-		l.Logger.Info("Reading String Loop:",
-			"prevChar", string(prvCh),
-			"prevPos", l.position-1,
+	if l.logging() {
+		l.Logger.Info("Reading String Start:",
 			"curChar", string(l.ch),
 			"curPosition", l.position,
 			"peekChar", string(l.peekChar()),
 			"peekCharPosition", l.position+1,
 		)
+	}
+
+ReadLoop:
+	for {
+		prvCh := l.ch
+		// A line break moves to the next line, so its own position is taken
+		// right after the previous character.
+		breakPos := token.Position{Line: l.line, Column: l.column + 1}
+		l.readChar()
+
+		if l.logging() {
+			l.Logger.Info("Reading String Loop:",
+				"prevChar", string(prvCh),
+				"prevPos", l.position-1,
+				"curChar", string(l.ch),
+				"curPosition", l.position,
+				"peekChar", string(l.peekChar()),
+				"peekCharPosition", l.position+1,
+			)
+		}
 
 		switch l.ch {
 		case '"':
@@ -113,83 +372,172 @@ ReadLoop:
 			}
 
 			if backslashCount%2 == 0 {
-				l.Logger.Info("Reading String Stopped Closing Quote:",
-					"prevChar", string(prvCh),
-					"curChar", string(l.ch),
-					"peekChar", string(l.peekChar()),
-				)
+				if l.logging() {
+					l.Logger.Info("Reading String Stopped Closing Quote:",
+						"prevChar", string(prvCh),
+						"curChar", string(l.ch),
+						"peekChar", string(l.peekChar()),
+					)
+				}
 				break ReadLoop
 			}
-		// case '\n', '\r':
-		// 	return token.Token{
-		// 		Type:     token.ILLEGAL,
-		// 		Literal:  l.input[start:l.position],
-		// 		Position: startPos,
-		// 	}
-		// case 0:
-		// 	l.Logger.Info("Reading String Stopped EOF:",
-		// 		"prevChar", string(prvCh),
-		// 		"curChar", string(l.ch),
-		// 		"peekChar", string(l.peekChar()),
-		// 	)
-		// 	if prvCh != '"' {
-		// 		return token.Token{
-		// 			Type:     token.ILLEGAL,
-		// 			Literal:  l.input[start:l.position],
-		// 			Position: startPos,
-		// 		}
-		// 	}
-		// 	break ReadLoop
+		case '\n', '\r':
+			return token.Token{
+				Type:     token.ILLEGAL,
+				Literal:  l.input[start:l.position],
+				Position: breakPos,
+				Reason:   "Strings may not contain raw line breaks; use \\n or \\r",
+			}
+		case 0:
+			if l.position >= len(l.input) {
+				return token.Token{
+					Type:     token.ILLEGAL,
+					Literal:  l.input[start:l.position],
+					Position: startPos,
+					Reason:   "Unterminated string",
+				}
+			}
+			fallthrough
 		default:
 			if l.ch >= 0 && l.ch <= 31 {
 				return token.Token{
 					Type:     token.ILLEGAL,
 					Literal:  l.input[start:l.position],
 					Position: startPos,
+					Reason:   fmt.Sprintf("Invalid control character %U in string", rune(l.ch)),
 				}
 			}
 		}
+
+		if l.maxStringLength > 0 && l.position-start+1 > l.maxStringLength {
+			if l.logging() {
+				l.Logger.Info("Reading String Stopped Max Length:",
+					"maxStringLength", l.maxStringLength,
+					"pos", startPos,
+				)
+			}
+			return token.Token{
+				Type:     token.ILLEGAL,
+				Literal:  l.input[start : l.position+1],
+				Position: startPos,
+				Reason:   fmt.Sprintf("String exceeds the maximum length of %d bytes", l.maxStringLength),
+			}
+		}
 	}
 
-	return token.Token{
+	tok := token.Token{
 		Type:     token.STRING,
 		Literal:  l.input[start:l.position],
 		Position: startPos,
 	}
+	if l.rawStrings {
+		tok.RawLiteral = l.input[start-1 : l.position+1]
+	}
+	return tok
+}
+
+func (l *Lexer) readComment() token.Token {
+	startPos := token.Position{Line: l.line, Column: l.column}
+	start := l.position
+	l.readChar()
+
+	if l.ch == '/' {
+		for l.peekChar() != '\n' && l.peekChar() != 0 {
+			l.readChar()
+		}
+
+		return token.Token{
+			Type:     token.COMMENT,
+			Literal:  l.input[start : l.position+1],
+			Position: startPos,
+		}
+	}
+
+	for {
+		l.readChar()
+
+		if l.ch == 0 {
+			return token.Token{
+				Type:     token.ILLEGAL,
+				Literal:  l.input[start:l.position],
+				Position: startPos,
+				Reason:   "Unterminated block comment",
+			}
+		}
+
+		if l.ch == '*' && l.peekChar() == '/' {
+			l.readChar()
+			if l.logging() {
+				l.Logger.Info("Reading Comment Completed:",
+					"literal", l.input[start:l.position+1],
+					"pos", startPos,
+				)
+			}
+			return token.Token{
+				Type:     token.COMMENT,
+				Literal:  l.input[start : l.position+1],
+				Position: startPos,
+			}
+		}
+	}
 }
 
 func (l *Lexer) readNumber() token.Token {
+	if l.hexNumbers && l.hasHexPrefix() {
+		return l.readHexNumber()
+	}
+
 	startPos := token.Position{Line: l.line, Column: l.column}
 	start := l.position
-	l.Logger.Info("Reading Number Started:",
-		"curChar", string(l.ch),
-		"curPosition", l.position,
-		"peekChar", string(l.peekChar()),
-		"peekCharPosition", start+1,
-	)
+	if l.logging() {
+		l.Logger.Info("Reading Number Started:",
+			"curChar", string(l.ch),
+			"curPosition", l.position,
+			"peekChar", string(l.peekChar()),
+			"peekCharPosition", start+1,
+		)
+	}
 
+	digits, exponent := 0, false
+	if l.isDigit(l.ch) {
+		digits++
+	}
 	for {
-		switch l.peekChar() {
-		case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9', '.', '-', '+', 'e', 'E':
+		switch {
+		case isNumberChar(l.peekChar()):
 			l.readChar()
-			l.Logger.Info("Reading Number Main Case:",
-				"curChar", string(l.ch),
-				"curPosition", l.position,
-				"peekChar", string(l.peekChar()),
-				"peekCharPosition", l.position+1,
-			)
+			if l.ch == 'e' || l.ch == 'E' {
+				digits, exponent = 0, true
+			} else if l.isDigit(l.ch) {
+				digits++
+			}
+			if l.maxNumberDigits > 0 && digits > l.maxNumberDigits {
+				return l.numberTooLong(start, startPos, exponent)
+			}
+			if l.logging() {
+				l.Logger.Info("Reading Number Main Case:",
+					"curChar", string(l.ch),
+					"curPosition", l.position,
+					"peekChar", string(l.peekChar()),
+					"peekCharPosition", l.position+1,
+				)
+			}
 		default:
 			numberStr := l.input[start : l.position+1]
-			l.Logger.Info("numberStr", "start", start, "end", l.position+1, "inputLen",
-				len(l.input), "input", l.input, "numberStr", numberStr)
-			numberRegex := regexp.MustCompile(`^[-]?(([1-9][0-9]*)|0)(\.[0-9]+)?([eE][-+]?[0-9]+)?$`)
+			if l.logging() {
+				l.Logger.Info("numberStr", "start", start, "end", l.position+1, "inputLen",
+					len(l.input), "input", l.input, "numberStr", numberStr)
+			}
+			reason := l.checkNumber(numberStr)
 			l.readChar()
-			if numberRegex.MatchString(numberStr) {
-				l.Logger.Info("Reading Number Completed:",
-					"tokenType", token.NUMBER,
-					"literal", numberStr,
-					"pos", startPos,
-				)
+			if reason == "" {
+				if l.logging() {
+					l.Logger.Info("Reading Number Completed:",
+						"tokenType", token.NUMBER,
+						"literal", numberStr,
+						"pos", startPos,
+					)
+				}
 				return token.Token{
 					Type:     token.NUMBER,
 					Literal:  numberStr,
@@ -197,39 +545,236 @@ func (l *Lexer) readNumber() token.Token {
 				}
 			}
 
-			l.Logger.Info("Reading Number Stopped:",
-				"tokenType", token.ILLEGAL,
-				"literal", numberStr,
-				"pos", startPos,
-			)
+			if l.logging() {
+				l.Logger.Info("Reading Number Stopped:",
+					"tokenType", token.ILLEGAL,
+					"literal", numberStr,
+					"reason", reason,
+					"pos", startPos,
+				)
+			}
 			return token.Token{
 				Type:     token.ILLEGAL,
 				Literal:  numberStr,
 				Position: startPos,
+				Reason:   reason,
 			}
 		}
 	}
 }
 
+// numberTooLong cuts the literal of a number at the digit that passed the
+// limit set with WithMaxNumberDigits, then skips the rest of the number so
+// it is not read as another token.
+func (l *Lexer) numberTooLong(start int, startPos token.Position, exponent bool) token.Token {
+	part := "mantissa"
+	if exponent {
+		part = "exponent"
+	}
+	reason := fmt.Sprintf("Number has more than %d digits in its %s", l.maxNumberDigits, part)
+	numberStr := l.input[start : l.position+1]
+	for isNumberChar(l.peekChar()) {
+		l.readChar()
+	}
+	l.readChar()
+
+	if l.logging() {
+		l.Logger.Info("Reading Number Stopped Max Digits:",
+			"tokenType", token.ILLEGAL,
+			"literal", numberStr,
+			"reason", reason,
+			"pos", startPos,
+		)
+	}
+	return token.Token{
+		Type:     token.ILLEGAL,
+		Literal:  numberStr,
+		Position: startPos,
+		Reason:   reason,
+	}
+}
+
+// isNumberChar reports whether ch may appear in a decimal number after its
+// first character.
+func isNumberChar(ch byte) bool {
+	switch ch {
+	case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9', '.', '-', '+', 'e', 'E':
+		return true
+	}
+	return false
+}
+
+func (l *Lexer) hasHexPrefix() bool {
+	i := l.position
+	if i < len(l.input) && l.input[i] == '-' {
+		i++
+	}
+	return i+1 < len(l.input) && l.input[i] == '0' && (l.input[i+1] == 'x' || l.input[i+1] == 'X')
+}
+
+// readHexNumber reads a hexadecimal integer, including its optional sign
+// and its 0x prefix.
+func (l *Lexer) readHexNumber() token.Token {
+	startPos := token.Position{Line: l.line, Column: l.column}
+	start := l.position
+
+	if l.ch == '-' {
+		l.readChar()
+	}
+	l.readChar()
+
+	for l.isLetter(l.peekChar()) || l.isDigit(l.peekChar()) {
+		l.readChar()
+	}
+
+	numberStr := l.input[start : l.position+1]
+	reason := l.checkHexNumber(numberStr)
+	l.readChar()
+
+	if reason != "" {
+		if l.logging() {
+			l.Logger.Info("Reading Hex Number Stopped:",
+				"tokenType", token.ILLEGAL,
+				"literal", numberStr,
+				"reason", reason,
+				"pos", startPos,
+			)
+		}
+		return token.Token{
+			Type:     token.ILLEGAL,
+			Literal:  numberStr,
+			Position: startPos,
+			Reason:   reason,
+		}
+	}
+
+	if l.logging() {
+		l.Logger.Info("Reading Hex Number Completed:",
+			"tokenType", token.NUMBER,
+			"literal", numberStr,
+			"pos", startPos,
+		)
+	}
+	return token.Token{
+		Type:     token.NUMBER,
+		Literal:  numberStr,
+		Position: startPos,
+	}
+}
+
+func (l *Lexer) checkHexNumber(numberStr string) string {
+	prefix := 2
+	if numberStr[0] == '-' {
+		prefix++
+	}
+
+	digits := numberStr[prefix:]
+	if digits == "" {
+		return fmt.Sprintf("Invalid number '%s': expected hex digit after '%s'", numberStr,
+			numberStr[prefix-2:prefix])
+	}
+
+	for i := 0; i < len(digits); i++ {
+		c := digits[i]
+		if !l.isDigit(c) && !('a' <= c && c <= 'f') && !('A' <= c && c <= 'F') {
+			return fmt.Sprintf("Invalid number '%s': unexpected '%c'", numberStr, c)
+		}
+	}
+	return ""
+}
+
+// checkNumber validates numberStr against the JSON number grammar and
+// returns why it is invalid, or an empty string when it is a valid number.
+func (l *Lexer) checkNumber(numberStr string) string {
+	i := 0
+	digits := func() int {
+		n := 0
+		for i < len(numberStr) && l.isDigit(numberStr[i]) {
+			i++
+			n++
+		}
+		return n
+	}
+	invalid := func(format string, a ...any) string {
+		return fmt.Sprintf("Invalid number '%s': ", numberStr) + fmt.Sprintf(format, a...)
+	}
+
+	sign := byte('-')
+	if i < len(numberStr) && (numberStr[i] == '-' || (l.leadingPlus && numberStr[i] == '+')) {
+		sign = numberStr[i]
+		i++
+	}
+
+	switch {
+	case i == len(numberStr):
+		return invalid("expected digit after '%c'", sign)
+	case numberStr[i] == '-' || numberStr[i] == '+':
+		return invalid("unexpected '%c'", numberStr[i])
+	case !l.isDigit(numberStr[i]):
+		return invalid("expected digit after '%c', got '%c'", sign, numberStr[i])
+	case numberStr[i] == '0':
+		i++
+		if i < len(numberStr) && l.isDigit(numberStr[i]) {
+			return invalid("leading zeros are not allowed")
+		}
+	default:
+		digits()
+	}
+
+	if i < len(numberStr) && numberStr[i] == '.' {
+		i++
+		if digits() == 0 {
+			return invalid("expected digit after decimal point")
+		}
+	}
+
+	if i < len(numberStr) && (numberStr[i] == 'e' || numberStr[i] == 'E') {
+		i++
+		if i < len(numberStr) && (numberStr[i] == '-' || numberStr[i] == '+') {
+			i++
+		}
+		if digits() == 0 {
+			return invalid("expected digit in exponent")
+		}
+	}
+
+	if i < len(numberStr) {
+		return invalid("unexpected '%c'", numberStr[i])
+	}
+	return ""
+}
+
 func (l *Lexer) readChar() {
+	// Once EOF has been read the lexer stays put, so the position never
+	// runs past the end of the input.
+	if l.readPosition > len(l.input) {
+		return
+	}
+
+	prev := l.ch
 	if l.readPosition >= len(l.input) {
 		l.ch = 0
 	} else {
 		l.ch = l.input[l.readPosition]
 	}
 
-	if l.ch == '\n' {
+	switch {
+	case l.ch == '\n':
 		l.line++
 		l.column = 0
-	} else {
+	case prev == '\t' && l.tabWidth > 1:
+		l.column += l.tabWidth
+	default:
 		l.column++
 	}
 
-	l.Logger.Info("Character Read:",
-		"char", string(l.ch),
-		"ascii", l.ch,
-		"position", l.readPosition,
-	)
+	if l.logging() {
+		l.Logger.Info("Character Read:",
+			"char", string(l.ch),
+			"ascii", l.ch,
+			"position", l.readPosition,
+		)
+	}
 
 	l.position = l.readPosition
 	l.readPosition++
@@ -259,6 +804,43 @@ func (l *Lexer) readIdentifier() string {
 	return l.input[position:l.position]
 }
 
+// pythonKeywords maps the Python spellings of the JSON keywords, often
+// left in by serializing with str() instead of json.dumps, to their JSON
+// counterparts.
+var pythonKeywords = map[string]string{
+	"True":  "true",
+	"False": "false",
+	"None":  "null",
+}
+
+// identifierHint suggests the JSON keyword meant by a word the lexer does
+// not know, e.g. 'true' for 'True' or 'null' for 'NULL'. It returns "" for
+// other words.
+func identifierHint(word string) string {
+	if keyword, ok := pythonKeywords[word]; ok {
+		return fmt.Sprintf("Python-style '%s' found; use '%s'", word, keyword)
+	}
+	switch lower := strings.ToLower(word); lower {
+	case "true", "false", "null":
+		return fmt.Sprintf("Keywords are lowercase in JSON; use '%s' instead of '%s'", lower, word)
+	}
+	return ""
+}
+
+// singleQuoteHint explains the single quote the lexer is on, showing the
+// double-quoted string when it is closed on the same line. Strings holding
+// escapes are not shown since they may mean something else once quoted.
+func (l *Lexer) singleQuoteHint() string {
+	rest := l.input[l.position+1:]
+	end := strings.IndexAny(rest, "'\n")
+	if end >= 0 && rest[end] == '\'' && !strings.Contains(rest[:end], "\\") {
+		str := rest[:end]
+		return fmt.Sprintf("Strings must use double quotes in JSON; use \"%s\" instead of '%s'",
+			strings.ReplaceAll(str, `"`, `\"`), str)
+	}
+	return "Strings must use double quotes in JSON, not single quotes"
+}
+
 func (l *Lexer) isLetter(ch byte) bool {
 	return 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || ch == '_'
 }
@@ -1,6 +1,9 @@
 package lexer
 
 import (
+	"context"
+	"log/slog"
+	"strings"
 	"testing"
 
 	"github.com/nobletk/json-parser/internal/token"
@@ -69,6 +72,12 @@ func TestNextToken(t *testing.T) {
 			expectedType:    token.NULL,
 			expectedLiteral: "null",
 		},
+		{
+			name:            "Undefined",
+			input:           "undefined",
+			expectedType:    token.UNDEFINED,
+			expectedLiteral: "undefined",
+		},
 		{
 			name:            "Regular String",
 			input:           "\"key1\"",
@@ -147,6 +156,12 @@ func TestNextToken(t *testing.T) {
 			expectedType:    token.ILLEGAL,
 			expectedLiteral: "key",
 		},
+		{
+			name:            "Illegal Lone Quotation Mark",
+			input:           "\"",
+			expectedType:    token.ILLEGAL,
+			expectedLiteral: "",
+		},
 		{
 			name:            "Illegal String With Unescaped Reverse Solidus",
 			input:           "\"string\\\"",
@@ -334,3 +349,834 @@ func TestNextToken(t *testing.T) {
 		})
 	}
 }
+
+func TestReset(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []token.Token
+	}{
+		{
+			name:  "First Document",
+			input: "{\"key\": 1}",
+			expected: []token.Token{
+				{Type: token.LBRACE, Literal: "{", Position: token.Position{Line: 1, Column: 1}},
+				{Type: token.STRING, Literal: "key", Position: token.Position{Line: 1, Column: 2}},
+				{Type: token.COLON, Literal: ":", Position: token.Position{Line: 1, Column: 7}},
+				{Type: token.NUMBER, Literal: "1", Position: token.Position{Line: 1, Column: 9}},
+				{Type: token.RBRACE, Literal: "}", Position: token.Position{Line: 1, Column: 10}},
+				{Type: token.EOF, Literal: "", Position: token.Position{Line: 1, Column: 11}},
+			},
+		},
+		{
+			name:  "Second Document",
+			input: "[\n  true,\n  null\n]",
+			expected: []token.Token{
+				{Type: token.LBRACKET, Literal: "[", Position: token.Position{Line: 1, Column: 1}},
+				{Type: token.TRUE, Literal: "true", Position: token.Position{Line: 2, Column: 3}},
+				{Type: token.COMMA, Literal: ",", Position: token.Position{Line: 2, Column: 7}},
+				{Type: token.NULL, Literal: "null", Position: token.Position{Line: 3, Column: 3}},
+				{Type: token.RBRACKET, Literal: "]", Position: token.Position{Line: 4, Column: 1}},
+				{Type: token.EOF, Literal: "", Position: token.Position{Line: 4, Column: 2}},
+			},
+		},
+	}
+
+	log := mylog.CreateLogger(true)
+	l := New(log, "")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l.Reset(tt.input)
+			assert.Equal(t, 0, l.TokenOffset(), "Reset() should clear the token offset")
+
+			for _, expected := range tt.expected {
+				tok := l.NextToken()
+				assert.Equal(t, expected, tok, "token isn't correct")
+			}
+		})
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    []token.Token
+		expectedErr *LexError
+	}{
+		{
+			name:  "Object With Array Value",
+			input: `{"a":[1,true]}`,
+			expected: []token.Token{
+				{Type: token.LBRACE, Literal: "{", Position: token.Position{Line: 1, Column: 1}},
+				{Type: token.STRING, Literal: "a", Position: token.Position{Line: 1, Column: 2}},
+				{Type: token.COLON, Literal: ":", Position: token.Position{Line: 1, Column: 5}},
+				{Type: token.LBRACKET, Literal: "[", Position: token.Position{Line: 1, Column: 6}},
+				{Type: token.NUMBER, Literal: "1", Position: token.Position{Line: 1, Column: 7}},
+				{Type: token.COMMA, Literal: ",", Position: token.Position{Line: 1, Column: 8}},
+				{Type: token.TRUE, Literal: "true", Position: token.Position{Line: 1, Column: 9}},
+				{Type: token.RBRACKET, Literal: "]", Position: token.Position{Line: 1, Column: 13}},
+				{Type: token.RBRACE, Literal: "}", Position: token.Position{Line: 1, Column: 14}},
+				{Type: token.EOF, Literal: "", Position: token.Position{Line: 1, Column: 15}},
+			},
+		},
+		{
+			name:  "Illegal Tokens Are Kept",
+			input: `[*, 1]`,
+			expected: []token.Token{
+				{Type: token.LBRACKET, Literal: "[", Position: token.Position{Line: 1, Column: 1}},
+				{Type: token.ILLEGAL, Literal: "*", Position: token.Position{Line: 1, Column: 2}},
+				{Type: token.COMMA, Literal: ",", Position: token.Position{Line: 1, Column: 3}},
+				{Type: token.NUMBER, Literal: "1", Position: token.Position{Line: 1, Column: 5}},
+				{Type: token.RBRACKET, Literal: "]", Position: token.Position{Line: 1, Column: 6}},
+				{Type: token.EOF, Literal: "", Position: token.Position{Line: 1, Column: 7}},
+			},
+			expectedErr: &LexError{
+				Msg: "Illegal token '*'\n",
+				Pos: token.Position{Line: 1, Column: 2},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := mylog.CreateLogger(true)
+			tokens, lexErr := Tokenize(log, tt.input)
+
+			assert.Equal(t, tt.expected, tokens, "tokens aren't correct")
+			assert.Equal(t, tt.expectedErr, lexErr)
+		})
+	}
+}
+
+func TestNextTokenComments(t *testing.T) {
+	tests := []struct {
+		name            string
+		input           string
+		opts            []Option
+		expectedType    token.TokenType
+		expectedLiteral string
+		expectedReason  string
+	}{
+		{
+			name:            "Line Comment",
+			input:           "// comment\n{",
+			opts:            []Option{WithComments()},
+			expectedType:    token.COMMENT,
+			expectedLiteral: "// comment",
+		},
+		{
+			name:            "Line Comment At EOF",
+			input:           "// comment",
+			opts:            []Option{WithComments()},
+			expectedType:    token.COMMENT,
+			expectedLiteral: "// comment",
+		},
+		{
+			name:            "Block Comment",
+			input:           "/* multi\nline */ {",
+			opts:            []Option{WithComments()},
+			expectedType:    token.COMMENT,
+			expectedLiteral: "/* multi\nline */",
+		},
+		{
+			name:            "Illegal Unterminated Block Comment",
+			input:           "/* comment",
+			opts:            []Option{WithComments()},
+			expectedType:    token.ILLEGAL,
+			expectedLiteral: "/* comment",
+			expectedReason:  "Unterminated block comment",
+		},
+		{
+			name:            "Illegal Comment Without Option",
+			input:           "// comment",
+			expectedType:    token.ILLEGAL,
+			expectedLiteral: "/",
+			expectedReason:  "Comments are not allowed in JSON; did you mean to enable comments?",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := mylog.CreateLogger(true)
+			l := New(log, tt.input, tt.opts...)
+			tok := l.NextToken()
+
+			assert.Equal(t, tt.expectedType, tok.Type, "tokenType isn't correct")
+			assert.Equal(t, tt.expectedLiteral, tok.Literal, "token.Literal isn't correct")
+			assert.Equal(t, tt.expectedReason, tok.Reason, "token.Reason isn't correct")
+		})
+	}
+}
+
+func TestNextTokenLoneQuotationMark(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []token.Token
+	}{
+		{
+			name:  "Lone Quotation Mark",
+			input: "\"",
+			expected: []token.Token{
+				{Type: token.ILLEGAL, Literal: "", Position: token.Position{Line: 1, Column: 1}, Reason: "Unterminated string"},
+				{Type: token.EOF, Literal: "", Position: token.Position{Line: 1, Column: 2}},
+			},
+		},
+		{
+			name:  "Quotation Mark At End Of Input",
+			input: "[\"",
+			expected: []token.Token{
+				{Type: token.LBRACKET, Literal: "[", Position: token.Position{Line: 1, Column: 1}},
+				{Type: token.ILLEGAL, Literal: "", Position: token.Position{Line: 1, Column: 2}, Reason: "Unterminated string"},
+				{Type: token.EOF, Literal: "", Position: token.Position{Line: 1, Column: 3}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := mylog.CreateLogger(true)
+			l := New(log, tt.input)
+
+			for _, expected := range tt.expected {
+				tok := l.NextToken()
+				assert.Equal(t, expected, tok, "token isn't correct")
+			}
+		})
+	}
+}
+
+func TestOffset(t *testing.T) {
+	input := "{\n  \"key\": [1, true, \"value\"],\n  \"other\": null\n}\n"
+	log := mylog.CreateLogger(true)
+	l := New(log, input)
+
+	assert.Equal(t, len(input), l.Len(), "Len() isn't correct")
+	assert.Equal(t, 0, l.Offset(), "Offset() should start at 0")
+
+	prevOffset := l.Offset()
+	for {
+		tok := l.NextToken()
+		assert.GreaterOrEqual(t, l.Offset(), prevOffset, "Offset() should not move backwards")
+		assert.LessOrEqual(t, l.Offset(), l.Len(), "Offset() should not exceed Len()")
+		prevOffset = l.Offset()
+
+		if tok.Type == token.EOF {
+			break
+		}
+	}
+
+	assert.Equal(t, l.Len(), l.Offset(), "Offset() should equal Len() at EOF")
+}
+
+func TestTokenOffset(t *testing.T) {
+	input := "{\n  \"key\": [12, true]\n}  "
+	expected := []int{0, 4, 9, 11, 12, 14, 16, 20, 22, 25}
+
+	log := mylog.CreateLogger(true)
+	l := New(log, input)
+
+	for i, exp := range expected {
+		l.NextToken()
+		assert.Equal(t, exp, l.TokenOffset(), "tests[%d] - token offset wrong", i)
+	}
+}
+
+func TestMaxStringLength(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		opts     []Option
+		expected token.Token
+	}{
+		{
+			name:  "String Within Limit",
+			input: "\"value\"",
+			opts:  []Option{WithMaxStringLength(5)},
+			expected: token.Token{
+				Type:     token.STRING,
+				Literal:  "value",
+				Position: token.Position{Line: 1, Column: 1},
+			},
+		},
+		{
+			name:  "String Exceeding Limit",
+			input: "\"values and more\"",
+			opts:  []Option{WithMaxStringLength(5)},
+			expected: token.Token{
+				Type:     token.ILLEGAL,
+				Literal:  "values",
+				Position: token.Position{Line: 1, Column: 1},
+				Reason:   "String exceeds the maximum length of 5 bytes",
+			},
+		},
+		{
+			name:  "Unlimited String",
+			input: "\"values\"",
+			expected: token.Token{
+				Type:     token.STRING,
+				Literal:  "values",
+				Position: token.Position{Line: 1, Column: 1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := mylog.CreateLogger(true)
+			l := New(log, tt.input, tt.opts...)
+			tok := l.NextToken()
+
+			assert.Equal(t, tt.expected, tok, "token isn't correct")
+		})
+	}
+}
+
+func TestMaxNumberDigits(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		opts     []Option
+		expected token.Token
+	}{
+		{
+			name:  "Number Within Limit",
+			input: "-123.456e+789",
+			opts:  []Option{WithMaxNumberDigits(6)},
+			expected: token.Token{
+				Type:     token.NUMBER,
+				Literal:  "-123.456e+789",
+				Position: token.Position{Line: 1, Column: 1},
+			},
+		},
+		{
+			name:  "Mantissa Exceeding Limit",
+			input: "1234567",
+			opts:  []Option{WithMaxNumberDigits(6)},
+			expected: token.Token{
+				Type:     token.ILLEGAL,
+				Literal:  "1234567",
+				Position: token.Position{Line: 1, Column: 1},
+				Reason:   "Number has more than 6 digits in its mantissa",
+			},
+		},
+		{
+			name:  "Exponent Exceeding Limit",
+			input: "1e" + strings.Repeat("9", 100000),
+			opts:  []Option{WithMaxNumberDigits(6)},
+			expected: token.Token{
+				Type:     token.ILLEGAL,
+				Literal:  "1e9999999",
+				Position: token.Position{Line: 1, Column: 1},
+				Reason:   "Number has more than 6 digits in its exponent",
+			},
+		},
+		{
+			name:  "Unlimited Number",
+			input: "1e" + strings.Repeat("9", 20),
+			expected: token.Token{
+				Type:     token.NUMBER,
+				Literal:  "1e" + strings.Repeat("9", 20),
+				Position: token.Position{Line: 1, Column: 1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := New(nil, tt.input, tt.opts...)
+			tok := l.NextToken()
+
+			assert.Equal(t, tt.expected, tok, "token isn't correct")
+			assert.Equal(t, token.TokenType(token.EOF), l.NextToken().Type, "the rest of the number should be skipped")
+		})
+	}
+}
+
+func TestMaxNumberDigitsTokenize(t *testing.T) {
+	l := New(nil, "[12345678, 1]", WithMaxNumberDigits(4))
+
+	types := []token.TokenType{}
+	for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+		types = append(types, tok.Type)
+	}
+
+	assert.Equal(t, []token.TokenType{token.LBRACKET, token.ILLEGAL, token.COMMA, token.NUMBER, token.RBRACKET}, types)
+}
+
+func TestWithRawStrings(t *testing.T) {
+	input := `{"k\u00e9y": "say \"hi\"\n"}`
+
+	l := New(nil, input, WithRawStrings())
+	tokens := []token.Token{}
+	for tok := l.NextToken(); ; tok = l.NextToken() {
+		tokens = append(tokens, tok)
+		if tok.Type == token.EOF {
+			break
+		}
+	}
+
+	expected := []token.Token{
+		{Type: token.LBRACE, Literal: "{", Position: token.Position{Line: 1, Column: 1}},
+		{
+			Type:       token.STRING,
+			Literal:    `k\u00e9y`,
+			Position:   token.Position{Line: 1, Column: 2},
+			RawLiteral: `"k\u00e9y"`,
+		},
+		{Type: token.COLON, Literal: ":", Position: token.Position{Line: 1, Column: 12}},
+		{
+			Type:       token.STRING,
+			Literal:    `say \"hi\"\n`,
+			Position:   token.Position{Line: 1, Column: 14},
+			RawLiteral: `"say \"hi\"\n"`,
+		},
+		{Type: token.RBRACE, Literal: "}", Position: token.Position{Line: 1, Column: 28}},
+		{Type: token.EOF, Literal: "", Position: token.Position{Line: 1, Column: 29}},
+	}
+	assert.Equal(t, expected, tokens)
+
+	tok := New(nil, `"a"`).NextToken()
+	assert.Empty(t, tok.RawLiteral, "raw literals are off by default")
+}
+
+func TestIllegalTokenReason(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		expectedReason string
+	}{
+		{
+			name:           "Leading Zero",
+			input:          "-056",
+			expectedReason: "Invalid number '-056': leading zeros are not allowed",
+		},
+		{
+			name:           "Minus Without Digits",
+			input:          "-f123",
+			expectedReason: "Invalid number '-': expected digit after '-'",
+		},
+		{
+			name:           "Lone Minus",
+			input:          "-",
+			expectedReason: "Invalid number '-': expected digit after '-'",
+		},
+		{
+			name:           "Double Minus",
+			input:          "--5",
+			expectedReason: "Invalid number '--5': unexpected '-'",
+		},
+		{
+			name:           "Minus Before Exponent",
+			input:          "-e5",
+			expectedReason: "Invalid number '-e5': expected digit after '-', got 'e'",
+		},
+		{
+			name:           "Decimal Point Without Digits",
+			input:          "1.e5",
+			expectedReason: "Invalid number '1.e5': expected digit after decimal point",
+		},
+		{
+			name:           "Exponent Without Digits",
+			input:          "1e+",
+			expectedReason: "Invalid number '1e+': expected digit in exponent",
+		},
+		{
+			name:           "Sign In The Middle",
+			input:          "12-3",
+			expectedReason: "Invalid number '12-3': unexpected '-'",
+		},
+		{
+			name:           "Unterminated String",
+			input:          "\"abc",
+			expectedReason: "Unterminated string",
+		},
+		{
+			name:           "Control Character In String",
+			input:          "\"ab\tc\"",
+			expectedReason: "Invalid control character U+0009 in string",
+		},
+		{
+			name:           "Newline In String",
+			input:          "\"ab\nc\"",
+			expectedReason: "Strings may not contain raw line breaks; use \\n or \\r",
+		},
+		{
+			name:           "Carriage Return In String",
+			input:          "\"ab\r\nc\"",
+			expectedReason: "Strings may not contain raw line breaks; use \\n or \\r",
+		},
+		{
+			name:           "Single Quoted String",
+			input:          "'abc'",
+			expectedReason: "Strings must use double quotes in JSON; use \"abc\" instead of 'abc'",
+		},
+		{
+			name:           "Single Quoted String With Double Quotes",
+			input:          `'say "hi"'`,
+			expectedReason: `Strings must use double quotes in JSON; use "say \"hi\"" instead of 'say "hi"'`,
+		},
+		{
+			name:           "Single Quoted String With Escapes",
+			input:          `'it\'s'`,
+			expectedReason: "Strings must use double quotes in JSON, not single quotes",
+		},
+		{
+			name:           "Unclosed Single Quote",
+			input:          "'abc\n'",
+			expectedReason: "Strings must use double quotes in JSON, not single quotes",
+		},
+		{
+			name:           "Python True",
+			input:          "True",
+			expectedReason: "Python-style 'True' found; use 'true'",
+		},
+		{
+			name:           "Python None",
+			input:          "None",
+			expectedReason: "Python-style 'None' found; use 'null'",
+		},
+		{
+			name:           "Uppercase Keyword",
+			input:          "NULL",
+			expectedReason: "Keywords are lowercase in JSON; use 'null' instead of 'NULL'",
+		},
+		{
+			name:           "Unknown Word",
+			input:          "abc",
+			expectedReason: "",
+		},
+		{
+			name:           "Line Comment Without WithComments",
+			input:          "// comment",
+			expectedReason: "Comments are not allowed in JSON; did you mean to enable comments?",
+		},
+		{
+			name:           "Block Comment Without WithComments",
+			input:          "/* comment */",
+			expectedReason: "Comments are not allowed in JSON; did you mean to enable comments?",
+		},
+		{
+			name:           "Lone Slash",
+			input:          "/",
+			expectedReason: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := mylog.CreateLogger(true)
+			l := New(log, tt.input)
+			tok := l.NextToken()
+
+			assert.Equal(t, token.TokenType(token.ILLEGAL), tok.Type, "tokenType isn't correct")
+			assert.Equal(t, tt.expectedReason, tok.Reason, "token.Reason isn't correct")
+		})
+	}
+}
+
+func TestNewNilLogger(t *testing.T) {
+	l := New(nil, `{"key": 1}`)
+	assert.NotNil(t, l.Logger, "Logger should default to a discarding logger")
+	assert.False(t, l.Logger.Enabled(context.Background(), slog.LevelError))
+
+	assert.Equal(t, token.TokenType(token.LBRACE), l.NextToken().Type)
+}
+
+func TestNewAt(t *testing.T) {
+	input := "{\"key\": [1,\n  true]}"
+	expected := []token.Token{
+		{Type: token.LBRACE, Literal: "{", Position: token.Position{Line: 5, Column: 4}},
+		{Type: token.STRING, Literal: "key", Position: token.Position{Line: 5, Column: 5}},
+		{Type: token.COLON, Literal: ":", Position: token.Position{Line: 5, Column: 10}},
+		{Type: token.LBRACKET, Literal: "[", Position: token.Position{Line: 5, Column: 12}},
+		{Type: token.NUMBER, Literal: "1", Position: token.Position{Line: 5, Column: 13}},
+		{Type: token.COMMA, Literal: ",", Position: token.Position{Line: 5, Column: 14}},
+		{Type: token.TRUE, Literal: "true", Position: token.Position{Line: 6, Column: 3}},
+		{Type: token.RBRACKET, Literal: "]", Position: token.Position{Line: 6, Column: 7}},
+		{Type: token.RBRACE, Literal: "}", Position: token.Position{Line: 6, Column: 8}},
+		{Type: token.EOF, Literal: "", Position: token.Position{Line: 6, Column: 9}},
+	}
+
+	log := mylog.CreateLogger(true)
+	l := NewAt(log, input, token.Position{Line: 5, Column: 4})
+
+	for i, exp := range expected {
+		tok := l.NextToken()
+		assert.Equal(t, exp, tok, "tests[%d] - token wrong", i)
+	}
+
+	l.Reset(input)
+	assert.Equal(t, 0, l.Offset(), "Offset() should stay relative to the input")
+	assert.Equal(t, expected[0], l.NextToken(), "Reset() should start at the base position")
+}
+
+func TestSkipLines(t *testing.T) {
+	input := "# header\r\n# more\n{\"a\": 1}\n# not skipped"
+	hash := func(line string) bool { return strings.HasPrefix(line, "#") }
+
+	l := New(mylog.CreateLogger(true), input)
+	assert.Equal(t, 2, l.SkipLines(hash))
+	assert.Equal(t, 0, l.SkipLines(hash), "nothing left to skip")
+
+	tok := l.NextToken()
+	assert.Equal(t, token.TokenType(token.LBRACE), tok.Type)
+	assert.Equal(t, token.Position{Line: 3, Column: 1}, tok.Position)
+	assert.Equal(t, 17, l.TokenOffset())
+
+	all := New(nil, "# only\n#header")
+	assert.Equal(t, 2, all.SkipLines(hash))
+	assert.Equal(t, token.TokenType(token.EOF), all.NextToken().Type)
+}
+
+func TestWithTabWidth(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []Option
+		expected token.Position
+	}{
+		{
+			name:     "Default Width",
+			expected: token.Position{Line: 2, Column: 4},
+		},
+		{
+			name:     "Width Four",
+			opts:     []Option{WithTabWidth(4)},
+			expected: token.Position{Line: 2, Column: 10},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := New(mylog.CreateLogger(true), "{\n\t\t1@}", tt.opts...)
+			for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+				if tok.Type == token.ILLEGAL {
+					assert.Equal(t, tt.expected, tok.Position)
+					return
+				}
+			}
+			t.Fatal("expected an ILLEGAL token")
+		})
+	}
+}
+
+func TestAllowHexNumbers(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		opts     []Option
+		expected []token.Token
+	}{
+		{
+			name:  "Lowercase Prefix",
+			input: "[0x1F]",
+			opts:  []Option{AllowHexNumbers()},
+			expected: []token.Token{
+				{Type: token.LBRACKET, Literal: "[", Position: token.Position{Line: 1, Column: 1}},
+				{Type: token.NUMBER, Literal: "0x1F", Position: token.Position{Line: 1, Column: 2}},
+				{Type: token.RBRACKET, Literal: "]", Position: token.Position{Line: 1, Column: 6}},
+			},
+		},
+		{
+			name:  "Uppercase Prefix",
+			input: "0Xabc",
+			opts:  []Option{AllowHexNumbers()},
+			expected: []token.Token{
+				{Type: token.NUMBER, Literal: "0Xabc", Position: token.Position{Line: 1, Column: 1}},
+				{Type: token.EOF, Literal: "", Position: token.Position{Line: 1, Column: 6}},
+			},
+		},
+		{
+			name:  "Negative Hex",
+			input: "-0x10,",
+			opts:  []Option{AllowHexNumbers()},
+			expected: []token.Token{
+				{Type: token.NUMBER, Literal: "-0x10", Position: token.Position{Line: 1, Column: 1}},
+				{Type: token.COMMA, Literal: ",", Position: token.Position{Line: 1, Column: 6}},
+			},
+		},
+		{
+			name:  "Decimal Numbers Unaffected",
+			input: "0.5 -0",
+			opts:  []Option{AllowHexNumbers()},
+			expected: []token.Token{
+				{Type: token.NUMBER, Literal: "0.5", Position: token.Position{Line: 1, Column: 1}},
+				{Type: token.NUMBER, Literal: "-0", Position: token.Position{Line: 1, Column: 5}},
+			},
+		},
+		{
+			name:  "Missing Hex Digits",
+			input: "0x]",
+			opts:  []Option{AllowHexNumbers()},
+			expected: []token.Token{
+				{
+					Type:     token.ILLEGAL,
+					Literal:  "0x",
+					Position: token.Position{Line: 1, Column: 1},
+					Reason:   "Invalid number '0x': expected hex digit after '0x'",
+				},
+			},
+		},
+		{
+			name:  "Invalid Hex Digit",
+			input: "0x1g",
+			opts:  []Option{AllowHexNumbers()},
+			expected: []token.Token{
+				{
+					Type:     token.ILLEGAL,
+					Literal:  "0x1g",
+					Position: token.Position{Line: 1, Column: 1},
+					Reason:   "Invalid number '0x1g': unexpected 'g'",
+				},
+			},
+		},
+		{
+			name:  "Strict Mode",
+			input: "0x1F",
+			expected: []token.Token{
+				{Type: token.NUMBER, Literal: "0", Position: token.Position{Line: 1, Column: 1}},
+				{Type: token.ILLEGAL, Literal: "x", Position: token.Position{Line: 1, Column: 2}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := mylog.CreateLogger(true)
+			l := New(log, tt.input, tt.opts...)
+
+			for i, exp := range tt.expected {
+				tok := l.NextToken()
+				assert.Equal(t, exp, tok, "tests[%d] - token wrong", i)
+			}
+		})
+	}
+}
+
+func TestAllowLeadingPlus(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		opts     []Option
+		expected []token.Token
+	}{
+		{
+			name:  "Integer",
+			input: "[+123]",
+			opts:  []Option{AllowLeadingPlus()},
+			expected: []token.Token{
+				{Type: token.LBRACKET, Literal: "[", Position: token.Position{Line: 1, Column: 1}},
+				{Type: token.NUMBER, Literal: "+123", Position: token.Position{Line: 1, Column: 2}},
+				{Type: token.RBRACKET, Literal: "]", Position: token.Position{Line: 1, Column: 6}},
+			},
+		},
+		{
+			name:  "Fraction And Exponent",
+			input: "+1.5e2",
+			opts:  []Option{AllowLeadingPlus()},
+			expected: []token.Token{
+				{Type: token.NUMBER, Literal: "+1.5e2", Position: token.Position{Line: 1, Column: 1}},
+				{Type: token.EOF, Literal: "", Position: token.Position{Line: 1, Column: 7}},
+			},
+		},
+		{
+			name:  "Missing Digits",
+			input: "+]",
+			opts:  []Option{AllowLeadingPlus()},
+			expected: []token.Token{
+				{
+					Type:     token.ILLEGAL,
+					Literal:  "+",
+					Position: token.Position{Line: 1, Column: 1},
+					Reason:   "Invalid number '+': expected digit after '+'",
+				},
+			},
+		},
+		{
+			name:  "Double Sign",
+			input: "+-1",
+			opts:  []Option{AllowLeadingPlus()},
+			expected: []token.Token{
+				{
+					Type:     token.ILLEGAL,
+					Literal:  "+-1",
+					Position: token.Position{Line: 1, Column: 1},
+					Reason:   "Invalid number '+-1': unexpected '-'",
+				},
+			},
+		},
+		{
+			name:  "Strict Mode",
+			input: "+123",
+			expected: []token.Token{
+				{Type: token.ILLEGAL, Literal: "+", Position: token.Position{Line: 1, Column: 1}},
+				{Type: token.NUMBER, Literal: "123", Position: token.Position{Line: 1, Column: 2}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := mylog.CreateLogger(true)
+			l := New(log, tt.input, tt.opts...)
+
+			for i, exp := range tt.expected {
+				tok := l.NextToken()
+				assert.Equal(t, exp, tok, "tests[%d] - token wrong", i)
+			}
+		})
+	}
+}
+
+func TestUTF16Input(t *testing.T) {
+	expected := []token.Token{
+		{Type: token.LBRACE, Literal: "{", Position: token.Position{Line: 1, Column: 1}},
+		{Type: token.STRING, Literal: "a", Position: token.Position{Line: 1, Column: 2}},
+		{Type: token.COLON, Literal: ":", Position: token.Position{Line: 1, Column: 5}},
+		{Type: token.NUMBER, Literal: "1", Position: token.Position{Line: 1, Column: 6}},
+		{Type: token.RBRACE, Literal: "}", Position: token.Position{Line: 1, Column: 7}},
+		{Type: token.EOF, Literal: "", Position: token.Position{Line: 1, Column: 8}},
+	}
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{
+			name:  "UTF-16LE",
+			input: "\xff\xfe{\x00\"\x00a\x00\"\x00:\x001\x00}\x00",
+		},
+		{
+			name:  "UTF-16BE",
+			input: "\xfe\xff\x00{\x00\"\x00a\x00\"\x00:\x001\x00}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := mylog.CreateLogger(true)
+			l := New(log, tt.input)
+
+			assert.Equal(t, len(`{"a":1}`), l.Len(), "Len() should count the decoded text")
+			for i, exp := range expected {
+				tok := l.NextToken()
+				assert.Equal(t, exp, tok, "tests[%d] - token wrong", i)
+			}
+		})
+	}
+}
+
+func TestUTF16SurrogatePair(t *testing.T) {
+	// ["😀"] in UTF-16LE, the emoji being the surrogate pair D83D DE00.
+	input := "\xff\xfe[\x00\"\x00\x3d\xd8\x00\xde\"\x00]\x00"
+
+	log := mylog.CreateLogger(true)
+	l := New(log, input)
+
+	l.NextToken()
+	tok := l.NextToken()
+	assert.Equal(t, token.TokenType(token.STRING), tok.Type)
+	assert.Equal(t, "😀", tok.Literal)
+}
@@ -0,0 +1,251 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/nobletk/json-parser/internal/ast"
+)
+
+// Encoder writes a JSON document to an io.Writer one event at a time, so
+// large documents can be generated without building an AST. It rejects
+// calls that would produce invalid JSON, such as a value in an object that
+// has no key, and leaves the output unchanged when it does. Once writing to
+// w fails, every call returns that error.
+type Encoder struct {
+	w      io.Writer
+	indent string
+
+	stack []encoderFrame
+	done  bool
+	err   error
+}
+
+type encoderFrame struct {
+	object bool
+	count  int
+	// keyed is set in an object between a key and its value.
+	keyed bool
+}
+
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// SetIndent makes the encoder put every key and element on its own line,
+// repeating indent once per nesting level, like ast.MarshalIndent. An
+// empty indent, the default, produces compact output.
+func (e *Encoder) SetIndent(indent string) {
+	e.indent = indent
+}
+
+func (e *Encoder) WriteStartObject() error {
+	return e.start(true)
+}
+
+func (e *Encoder) WriteStartArray() error {
+	return e.start(false)
+}
+
+func (e *Encoder) WriteEndObject() error {
+	return e.end(true)
+}
+
+func (e *Encoder) WriteEndArray() error {
+	return e.end(false)
+}
+
+// WriteKey writes the key of the next member of the current object.
+func (e *Encoder) WriteKey(key string) error {
+	if e.err != nil {
+		return e.err
+	}
+	top := e.top()
+	if top == nil || !top.object {
+		return errors.New("key written outside an object")
+	}
+	if top.keyed {
+		return fmt.Errorf("key %q written where a value is expected", key)
+	}
+
+	text, err := marshalScalar(key)
+	if err != nil {
+		return err
+	}
+
+	e.separate(top)
+	e.write(text + ":")
+	if e.indent != "" {
+		e.write(" ")
+	}
+	top.keyed = true
+	return e.err
+}
+
+func (e *Encoder) WriteString(s string) error {
+	return e.writeScalar(s)
+}
+
+// WriteNumber writes f in the shortest form that parses back to it. NaN
+// and infinities are rejected.
+func (e *Encoder) WriteNumber(f float64) error {
+	return e.writeScalar(f)
+}
+
+func (e *Encoder) WriteInt(n int64) error {
+	return e.writeScalar(n)
+}
+
+func (e *Encoder) WriteBool(b bool) error {
+	return e.writeScalar(b)
+}
+
+func (e *Encoder) WriteNull() error {
+	return e.writeScalar(nil)
+}
+
+// Close reports an error when the document is not complete, e.g. when an
+// object is still open. It does not close the underlying writer.
+func (e *Encoder) Close() error {
+	if e.err != nil {
+		return e.err
+	}
+	if top := e.top(); top != nil && top.object {
+		return errors.New("unclosed object")
+	} else if top != nil {
+		return errors.New("unclosed array")
+	}
+	if !e.done {
+		return errors.New("no value written")
+	}
+	return nil
+}
+
+func (e *Encoder) start(object bool) error {
+	if err := e.beginValue(); err != nil {
+		return err
+	}
+
+	if object {
+		e.write("{")
+	} else {
+		e.write("[")
+	}
+	e.stack = append(e.stack, encoderFrame{object: object})
+	return e.err
+}
+
+func (e *Encoder) end(object bool) error {
+	if e.err != nil {
+		return e.err
+	}
+
+	top, name, closing := e.top(), "array", "]"
+	if object {
+		name, closing = "object", "}"
+	}
+	if top == nil || top.object != object {
+		return fmt.Errorf("end of %s written outside an %s", name, name)
+	}
+	if top.keyed {
+		return errors.New("end of object written where a value is expected")
+	}
+
+	e.stack = e.stack[:len(e.stack)-1]
+	if top.count > 0 {
+		e.newline()
+	}
+	e.write(closing)
+	e.endValue()
+	return e.err
+}
+
+func (e *Encoder) writeScalar(v interface{}) error {
+	text, err := marshalScalar(v)
+	if err != nil {
+		return err
+	}
+
+	if err := e.beginValue(); err != nil {
+		return err
+	}
+
+	e.write(text)
+	e.endValue()
+	return e.err
+}
+
+// beginValue checks that a value may be written next and writes the
+// separator in front of it.
+func (e *Encoder) beginValue() error {
+	if e.err != nil {
+		return e.err
+	}
+
+	top := e.top()
+	switch {
+	case top == nil && e.done:
+		return errors.New("value written after the document is complete")
+	case top != nil && top.object && !top.keyed:
+		return errors.New("value written where a key is expected")
+	case top != nil && !top.object:
+		e.separate(top)
+	}
+	return e.err
+}
+
+// endValue records that a value was completed.
+func (e *Encoder) endValue() {
+	top := e.top()
+	if top == nil {
+		e.done = true
+		return
+	}
+	if top.object {
+		top.keyed = false
+	}
+	top.count++
+}
+
+// separate writes the comma and indentation in front of the next member
+// or element of top.
+func (e *Encoder) separate(top *encoderFrame) {
+	if top.count > 0 {
+		e.write(",")
+	}
+	e.newline()
+}
+
+// newline starts a new line indented to the current depth.
+func (e *Encoder) newline() {
+	if e.indent != "" {
+		e.write("\n" + strings.Repeat(e.indent, len(e.stack)))
+	}
+}
+
+func (e *Encoder) top() *encoderFrame {
+	if len(e.stack) == 0 {
+		return nil
+	}
+	return &e.stack[len(e.stack)-1]
+}
+
+func (e *Encoder) write(s string) {
+	if e.err == nil {
+		_, e.err = io.WriteString(e.w, s)
+	}
+}
+
+func marshalScalar(v interface{}) (string, error) {
+	el, err := ast.FromInterface(v)
+	if err != nil {
+		return "", err
+	}
+	text, err := ast.Marshal(el)
+	if err != nil {
+		return "", err
+	}
+	return string(text), nil
+}
@@ -0,0 +1,199 @@
+package parser
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/nobletk/json-parser/internal/ast"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodeNested(t *testing.T, enc *Encoder) {
+	t.Helper()
+
+	require.NoError(t, enc.WriteStartObject())
+	require.NoError(t, enc.WriteKey("name"))
+	require.NoError(t, enc.WriteString("a \"quoted\"\nline"))
+	require.NoError(t, enc.WriteKey("values"))
+	require.NoError(t, enc.WriteStartArray())
+	require.NoError(t, enc.WriteInt(1))
+	require.NoError(t, enc.WriteNumber(-0.25))
+	require.NoError(t, enc.WriteBool(true))
+	require.NoError(t, enc.WriteNull())
+	require.NoError(t, enc.WriteStartObject())
+	require.NoError(t, enc.WriteEndObject())
+	require.NoError(t, enc.WriteEndArray())
+	require.NoError(t, enc.WriteKey("empty"))
+	require.NoError(t, enc.WriteStartArray())
+	require.NoError(t, enc.WriteEndArray())
+	require.NoError(t, enc.WriteEndObject())
+	require.NoError(t, enc.Close())
+}
+
+func TestEncoder(t *testing.T) {
+	tests := []struct {
+		name     string
+		indent   string
+		expected string
+	}{
+		{
+			name:     "Compact",
+			expected: `{"name":"a \"quoted\"\nline","values":[1,-0.25,true,null,{}],"empty":[]}`,
+		},
+		{
+			name:   "Indented",
+			indent: "  ",
+			expected: `{
+  "name": "a \"quoted\"\nline",
+  "values": [
+    1,
+    -0.25,
+    true,
+    null,
+    {}
+  ],
+  "empty": []
+}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			enc := NewEncoder(&out)
+			enc.SetIndent(tt.indent)
+
+			encodeNested(t, enc)
+			assert.Equal(t, tt.expected, out.String())
+
+			jf, jErr := ParseString(out.String())
+			require.Empty(t, jErr, "output should parse")
+			expected, jErr := ParseString(`{"name": "a \"quoted\"\nline", "values": [1, -0.25, true, null, {}], "empty": []}`)
+			require.Empty(t, jErr, "jsonErr should be empty")
+			assert.True(t, ast.Equal(expected.Elements[0], jf.Elements[0]))
+
+			out2, err := ast.MarshalIndent(jf.Elements[0], tt.indent)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(out2), "should match ast.MarshalIndent")
+		})
+	}
+}
+
+func TestEncoderMisuse(t *testing.T) {
+	tests := []struct {
+		name     string
+		events   func(enc *Encoder) error
+		expected string
+		written  string
+	}{
+		{
+			name: "Value Without Key",
+			events: func(enc *Encoder) error {
+				enc.WriteStartObject()
+				return enc.WriteString("value")
+			},
+			expected: "value written where a key is expected",
+			written:  "{",
+		},
+		{
+			name: "Key In Array",
+			events: func(enc *Encoder) error {
+				enc.WriteStartArray()
+				return enc.WriteKey("key")
+			},
+			expected: "key written outside an object",
+			written:  "[",
+		},
+		{
+			name: "Two Keys",
+			events: func(enc *Encoder) error {
+				enc.WriteStartObject()
+				enc.WriteKey("a")
+				return enc.WriteKey("b")
+			},
+			expected: `key "b" written where a value is expected`,
+			written:  `{"a":`,
+		},
+		{
+			name: "Mismatched End",
+			events: func(enc *Encoder) error {
+				enc.WriteStartObject()
+				return enc.WriteEndArray()
+			},
+			expected: "end of array written outside an array",
+			written:  "{",
+		},
+		{
+			name: "End After Key",
+			events: func(enc *Encoder) error {
+				enc.WriteStartObject()
+				enc.WriteKey("a")
+				return enc.WriteEndObject()
+			},
+			expected: "end of object written where a value is expected",
+			written:  `{"a":`,
+		},
+		{
+			name: "Second Root",
+			events: func(enc *Encoder) error {
+				enc.WriteInt(1)
+				return enc.WriteInt(2)
+			},
+			expected: "value written after the document is complete",
+			written:  "1",
+		},
+		{
+			name: "NaN",
+			events: func(enc *Encoder) error {
+				enc.WriteStartArray()
+				enc.WriteInt(1)
+				return enc.WriteNumber(math.NaN())
+			},
+			expected: "cannot convert NaN to a JSON number",
+			written:  "[1",
+		},
+		{
+			name: "Unclosed",
+			events: func(enc *Encoder) error {
+				enc.WriteStartArray()
+				enc.WriteStartObject()
+				enc.WriteEndObject()
+				return enc.Close()
+			},
+			expected: "unclosed array",
+			written:  "[{}",
+		},
+		{
+			name: "Nothing Written",
+			events: func(enc *Encoder) error {
+				return enc.Close()
+			},
+			expected: "no value written",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			enc := NewEncoder(&out)
+
+			assert.EqualError(t, tt.events(enc), tt.expected)
+			assert.Equal(t, tt.written, out.String())
+		})
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestEncoderWriteError(t *testing.T) {
+	enc := NewEncoder(failingWriter{})
+
+	assert.EqualError(t, enc.WriteStartArray(), "disk full")
+	assert.EqualError(t, enc.WriteInt(1), "disk full")
+	assert.EqualError(t, enc.Close(), "disk full")
+}
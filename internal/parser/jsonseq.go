@@ -0,0 +1,74 @@
+package parser
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/nobletk/json-parser/internal/ast"
+	"github.com/nobletk/json-parser/internal/lexer"
+	"github.com/nobletk/json-parser/internal/token"
+)
+
+// JSONSeqReader reads an RFC 7464 JSON text sequence (application/json-seq),
+// where every value is preceded by RecordSeparator and followed by a line
+// feed, one value at a time.
+type JSONSeqReader struct {
+	r    *bufio.Reader
+	opts []Option
+	p    *Parser
+
+	pos  token.Position
+	done bool
+}
+
+func NewJSONSeqReader(r io.Reader, opts ...Option) *JSONSeqReader {
+	return &JSONSeqReader{
+		r:    bufio.NewReader(r),
+		opts: opts,
+		pos:  token.Position{Line: 1, Column: 1},
+	}
+}
+
+// Next parses the next value and returns nil once the input is exhausted.
+// An invalid value that is not followed by a line feed was truncated, e.g.
+// by a writer that crashed, and is skipped as RFC 7464 recommends. Other
+// invalid values are reported, and calling Next again continues with the
+// value after them.
+func (sr *JSONSeqReader) Next() (*ast.JSONFile, *JSONErr) {
+	for !sr.done {
+		start := sr.pos
+		text, err := sr.r.ReadString(RecordSeparator)
+		if err != nil && !errors.Is(err, io.EOF) {
+			sr.done = true
+			msg := fmt.Sprintf("Failed to read record: %s\n", err)
+			return nil, &JSONErr{Msg: msg, Pos: start}
+		}
+		sr.done = err != nil
+		sr.pos = advance(start, text)
+
+		record := strings.TrimSuffix(text, string(RecordSeparator))
+		if strings.TrimSpace(record) == "" {
+			continue
+		}
+
+		doc, jErr := sr.parse(record, start)
+		if jErr != nil && !strings.HasSuffix(record, "\n") {
+			continue
+		}
+		return doc, jErr
+	}
+	return nil, nil
+}
+
+func (sr *JSONSeqReader) parse(record string, start token.Position) (*ast.JSONFile, *JSONErr) {
+	l := lexer.NewAt(nil, record, start)
+	if sr.p == nil {
+		sr.p = New(l, sr.opts...)
+	} else {
+		sr.p.Reset(l)
+	}
+	return sr.p.ParseFile()
+}
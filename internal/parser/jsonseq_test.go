@@ -0,0 +1,87 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nobletk/json-parser/internal/token"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONSeqReader(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			name:     "Two Records",
+			input:    "\x1e{\"id\": 1}\n\x1e[2]\n",
+			expected: []string{`{"id":1}`, `[2]`},
+		},
+		{
+			name:     "Truncated Final Record",
+			input:    "\x1e{\"id\": 1}\n\x1e{\"id\": 2}\n\x1e{\"id\": ",
+			expected: []string{`{"id":1}`, `{"id":2}`},
+		},
+		{
+			name:     "Truncated Record Before Another",
+			input:    "\x1e[1, 2\x1e[3]\n",
+			expected: []string{`[3]`},
+		},
+		{
+			name:     "Final Record Without Line Feed",
+			input:    "\x1e[1]\n\x1e[2]",
+			expected: []string{`[1]`, `[2]`},
+		},
+		{
+			name:     "Empty Records",
+			input:    "\x1e\x1e\n\x1e[1]\n",
+			expected: []string{`[1]`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sr := NewJSONSeqReader(strings.NewReader(tt.input))
+
+			out := []string{}
+			for {
+				doc, jErr := sr.Next()
+				require.Nil(t, jErr, "jsonErr should be nil")
+				if doc == nil {
+					break
+				}
+				out = append(out, doc.String())
+			}
+			assert.Equal(t, tt.expected, out)
+		})
+	}
+}
+
+func TestJSONSeqReaderInvalidRecord(t *testing.T) {
+	sr := NewJSONSeqReader(strings.NewReader("\x1e[1]\n\x1e{\n  \"id\": }\n\x1e[3]\n"))
+
+	doc, jErr := sr.Next()
+	require.Nil(t, jErr, "jsonErr should be nil")
+	assert.Equal(t, `[1]`, doc.String())
+
+	doc, jErr = sr.Next()
+	assert.Nil(t, doc)
+	assert.Equal(t, &JSONErr{
+		Msg: "Missing value for key 'id'\n",
+		Pos: token.Position{
+			Column: 9,
+			Line:   3,
+		},
+	}, jErr)
+
+	doc, jErr = sr.Next()
+	require.Nil(t, jErr, "reading should continue after an invalid record")
+	assert.Equal(t, `[3]`, doc.String())
+
+	doc, jErr = sr.Next()
+	assert.Nil(t, doc)
+	assert.Nil(t, jErr)
+}
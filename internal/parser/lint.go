@@ -0,0 +1,64 @@
+package parser
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/nobletk/json-parser/internal/token"
+)
+
+// Warning points at something in a valid document that a linter may want
+// to flag, see LintNumbers.
+type Warning struct {
+	Msg string
+	Pos token.Position
+}
+
+// Warnings returns the warnings found since the parser was created or
+// last Reset, in the order of the input.
+func (p *Parser) Warnings() []Warning {
+	return p.warnings
+}
+
+func (p *Parser) warn(msg string, pos token.Position) {
+	p.warnings = append(p.warnings, Warning{Msg: msg, Pos: pos})
+}
+
+// maxPlainExponent bounds the exponents lintNumber expands to suggest a
+// plain integer, so a literal like 1e999999 is not written out in full.
+const maxPlainExponent = 100
+
+// lintNumber warns about a decimal number literal that is not canonical.
+func (p *Parser) lintNumber(tok token.Token) {
+	literal := strings.TrimLeft(tok.Literal, "+-")
+	mantissa, exponent, hasExponent := strings.Cut(strings.ToLower(literal), "e")
+
+	if _, fraction, ok := strings.Cut(mantissa, "."); ok && strings.HasSuffix(fraction, "0") {
+		p.warn(fmt.Sprintf("Number %q has trailing zeros after the decimal point\n", tok.Literal), tok.Position)
+	}
+
+	if !hasExponent {
+		return
+	}
+
+	if strings.Contains(literal, "E") {
+		p.warn(fmt.Sprintf("Number %q uses an uppercase exponent 'E'\n", tok.Literal), tok.Position)
+	}
+
+	if strings.HasPrefix(exponent, "+") {
+		p.warn(fmt.Sprintf("Number %q has a redundant '+' in its exponent\n", tok.Literal), tok.Position)
+	}
+
+	if exp, err := strconv.Atoi(exponent); err != nil || exp > maxPlainExponent || exp < -maxPlainExponent {
+		return
+	}
+	value, ok := new(big.Rat).SetString(strings.TrimPrefix(tok.Literal, "+"))
+	if !ok || !value.IsInt() {
+		return
+	}
+	if plain := value.Num().String(); len(plain) <= len(tok.Literal) {
+		p.warn(fmt.Sprintf("Number %q could be written as %s\n", tok.Literal, plain), tok.Position)
+	}
+}
@@ -0,0 +1,69 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/nobletk/json-parser/internal/lexer"
+	"github.com/nobletk/json-parser/internal/token"
+	"github.com/nobletk/json-parser/pkg/mylog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintNumbers(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []Warning
+	}{
+		{
+			name:  "Trailing Zero",
+			input: `[1.0, 2.5]`,
+			expected: []Warning{
+				{Msg: "Number \"1.0\" has trailing zeros after the decimal point\n", Pos: token.Position{Column: 2, Line: 1}},
+			},
+		},
+		{
+			name:  "Uppercase Exponent",
+			input: `{"n": 1E2}`,
+			expected: []Warning{
+				{Msg: "Number \"1E2\" uses an uppercase exponent 'E'\n", Pos: token.Position{Column: 7, Line: 1}},
+				{Msg: "Number \"1E2\" could be written as 100\n", Pos: token.Position{Column: 7, Line: 1}},
+			},
+		},
+		{
+			name:  "Exponent Plus Sign",
+			input: "[\n  -2.50e+1\n]",
+			expected: []Warning{
+				{Msg: "Number \"-2.50e+1\" has trailing zeros after the decimal point\n", Pos: token.Position{Column: 3, Line: 2}},
+				{Msg: "Number \"-2.50e+1\" has a redundant '+' in its exponent\n", Pos: token.Position{Column: 3, Line: 2}},
+				{Msg: "Number \"-2.50e+1\" could be written as -25\n", Pos: token.Position{Column: 3, Line: 2}},
+			},
+		},
+		{
+			name:     "Canonical Numbers",
+			input:    `[0, -1, 1.5, 1e10, 2.5e-3, 1e999]`,
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := mylog.CreateLogger(true)
+			l := lexer.New(log, tt.input)
+			p := New(l, LintNumbers(), ClampNumbers())
+			_, jsonErr := p.ParseFile()
+			require.Empty(t, jsonErr, "jsonErr should be empty")
+
+			assert.Equal(t, tt.expected, p.Warnings())
+		})
+	}
+}
+
+func TestLintNumbersDisabled(t *testing.T) {
+	p := New(lexer.New(nil, `[1.0, 1E2]`))
+	_, jsonErr := p.ParseFile()
+	require.Empty(t, jsonErr, "jsonErr should be empty")
+
+	assert.Empty(t, p.Warnings())
+}
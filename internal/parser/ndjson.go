@@ -0,0 +1,87 @@
+package parser
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/nobletk/json-parser/internal/ast"
+	"github.com/nobletk/json-parser/internal/lexer"
+	"github.com/nobletk/json-parser/internal/token"
+)
+
+// ParseNDJSON parses newline-delimited JSON from r, one value per line, and
+// calls fn with each record and its line number as soon as it is parsed, so
+// the records are never held in memory together. Blank lines are skipped.
+//
+// Errors in a record point at its line in r, with the column counted from
+// the start of that line. Parsing stops at the first invalid record or when
+// fn returns an error, which is then reported at the record's line.
+func ParseNDJSON(r io.Reader, fn func(doc *ast.JSONFile, line int) error, opts ...Option) *JSONErr {
+	var stopErr *JSONErr
+
+	readErr := readNDJSON(r, func(doc *ast.JSONFile, line int, jErr *JSONErr) bool {
+		if jErr != nil {
+			stopErr = jErr
+			return false
+		}
+		if fnErr := fn(doc, line); fnErr != nil {
+			msg := fmt.Sprintf("%s\n", fnErr)
+			stopErr = &JSONErr{Msg: msg, Pos: token.Position{Line: line, Column: 1}}
+			return false
+		}
+		return true
+	}, opts...)
+
+	if readErr != nil {
+		return readErr
+	}
+	return stopErr
+}
+
+// ParseNDJSONAll is like ParseNDJSON but keeps going past invalid records,
+// e.g. to report every bad line of a file. fn is called for every record
+// with either its document or its error. The returned error only reports
+// that r could not be read.
+func ParseNDJSONAll(r io.Reader, fn func(doc *ast.JSONFile, line int, jErr *JSONErr), opts ...Option) *JSONErr {
+	return readNDJSON(r, func(doc *ast.JSONFile, line int, jErr *JSONErr) bool {
+		fn(doc, line, jErr)
+		return true
+	}, opts...)
+}
+
+// readNDJSON parses the records of r and passes each result to fn until it
+// returns false.
+func readNDJSON(r io.Reader, fn func(doc *ast.JSONFile, line int, jErr *JSONErr) bool, opts ...Option) *JSONErr {
+	br := bufio.NewReader(r)
+	var p *Parser
+
+	for line := 1; ; line++ {
+		text, err := br.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			msg := fmt.Sprintf("Failed to read record: %s\n", err)
+			return &JSONErr{Msg: msg, Pos: token.Position{Line: line, Column: 1}}
+		}
+
+		record := strings.TrimSuffix(strings.TrimSuffix(text, "\n"), "\r")
+		if strings.TrimSpace(record) != "" {
+			l := lexer.NewAt(nil, record, token.Position{Line: line, Column: 1})
+			if p == nil {
+				p = New(l, opts...)
+			} else {
+				p.Reset(l)
+			}
+
+			doc, jErr := p.ParseFile()
+			if !fn(doc, line, jErr) {
+				return nil
+			}
+		}
+
+		if err != nil {
+			return nil
+		}
+	}
+}
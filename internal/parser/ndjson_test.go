@@ -0,0 +1,125 @@
+package parser
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/nobletk/json-parser/internal/ast"
+	"github.com/nobletk/json-parser/internal/token"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseNDJSON(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		expected      []interface{}
+		expectedLines []int
+		expectedErr   *JSONErr
+	}{
+		{
+			name:  "Three Records",
+			input: "{\"id\": 1}\n{\"id\": 2}\n[3]\n",
+			expected: []interface{}{
+				map[string]interface{}{"id": float64(1)},
+				map[string]interface{}{"id": float64(2)},
+				[]interface{}{float64(3)},
+			},
+			expectedLines: []int{1, 2, 3},
+		},
+		{
+			name:  "Blank Lines And CRLF",
+			input: "{\"id\": 1}\r\n\r\n  \n[2]",
+			expected: []interface{}{
+				map[string]interface{}{"id": float64(1)},
+				[]interface{}{float64(2)},
+			},
+			expectedLines: []int{1, 4},
+		},
+		{
+			name:          "Empty Input",
+			input:         "",
+			expected:      []interface{}{},
+			expectedLines: []int{},
+		},
+		{
+			name:  "Invalid Record",
+			input: "{\"id\": 1}\n{\"id\": }\n{\"id\": 3}\n",
+			expected: []interface{}{
+				map[string]interface{}{"id": float64(1)},
+			},
+			expectedLines: []int{1},
+			expectedErr: &JSONErr{
+				Msg: "Missing value for key 'id'\n",
+				Pos: token.Position{
+					Column: 8,
+					Line:   2,
+				},
+			},
+		},
+		{
+			name:          "Two Values On One Line",
+			input:         "{\"id\": 1} {\"id\": 2}\n",
+			expected:      []interface{}{},
+			expectedLines: []int{},
+			expectedErr: &JSONErr{
+				Msg: "Unexpected trailing content after JSON value\n",
+				Pos: token.Position{
+					Column: 11,
+					Line:   1,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records := []interface{}{}
+			lines := []int{}
+
+			jErr := ParseNDJSON(strings.NewReader(tt.input), func(doc *ast.JSONFile, line int) error {
+				records = append(records, doc.ToInterface())
+				lines = append(lines, line)
+				return nil
+			})
+
+			assert.Equal(t, tt.expectedErr, jErr)
+			assert.Equal(t, tt.expected, records)
+			assert.Equal(t, tt.expectedLines, lines)
+		})
+	}
+}
+
+func TestParseNDJSONAbort(t *testing.T) {
+	input := "{\"id\": 1}\n{\"id\": 2}\n{\"id\": 3}\n"
+	lines := []int{}
+
+	jErr := ParseNDJSON(strings.NewReader(input), func(doc *ast.JSONFile, line int) error {
+		lines = append(lines, line)
+		if line == 2 {
+			return errors.New("stop at record 2")
+		}
+		return nil
+	})
+
+	assert.Equal(t, &JSONErr{Msg: "stop at record 2\n", Pos: token.Position{Column: 1, Line: 2}}, jErr)
+	assert.Equal(t, []int{1, 2}, lines)
+}
+
+func TestParseNDJSONAll(t *testing.T) {
+	input := "{\"id\": 1}\n{\"id\": }\n{\"id\": 3}\n"
+	lines := []int{}
+	errs := []*JSONErr{}
+
+	jErr := ParseNDJSONAll(strings.NewReader(input), func(doc *ast.JSONFile, line int, jErr *JSONErr) {
+		lines = append(lines, line)
+		errs = append(errs, jErr)
+	})
+
+	assert.Nil(t, jErr)
+	assert.Equal(t, []int{1, 2, 3}, lines)
+	assert.Nil(t, errs[0])
+	assert.Equal(t, &JSONErr{Msg: "Missing value for key 'id'\n", Pos: token.Position{Column: 8, Line: 2}}, errs[1])
+	assert.Nil(t, errs[2])
+}
@@ -1,10 +1,18 @@
 package parser
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"math"
+	"math/big"
 	"regexp"
+	"slices"
 	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/nobletk/json-parser/internal/ast"
 	"github.com/nobletk/json-parser/internal/lexer"
@@ -15,11 +23,60 @@ type (
 	parseFn func() (ast.Element, *JSONErr)
 )
 
+// DefaultMaxDepth is the nesting depth of objects and arrays allowed unless
+// WithMaxDepth says otherwise.
+const DefaultMaxDepth = 1000
+
 type JSONErr struct {
 	Msg string
 	Pos token.Position
 }
 
+// Context returns the line of input the error points at with a caret under
+// its column, e.g.
+//
+//	{"key": }
+//	        ^
+//
+// Tabs before the column are kept so the caret lines up with the source.
+// It returns an empty string when Pos is not within input.
+func (je *JSONErr) Context(input string) string {
+	return je.ContextTabWidth(input, 1)
+}
+
+// ContextTabWidth is like Context for an error found by a lexer created
+// with lexer.WithTabWidth(tabWidth), whose columns count a tab as tabWidth.
+func (je *JSONErr) ContextTabWidth(input string, tabWidth int) string {
+	lines := strings.Split(input, "\n")
+	if je.Pos.Line < 1 || je.Pos.Line > len(lines) {
+		return ""
+	}
+
+	line := strings.TrimSuffix(lines[je.Pos.Line-1], "\r")
+
+	// Turn the column back into a byte offset in line.
+	offset := 0
+	for column := 1; offset < len(line) && column < je.Pos.Column; offset++ {
+		if line[offset] == '\t' && tabWidth > 1 {
+			column += tabWidth
+		} else {
+			column++
+		}
+	}
+
+	var caret strings.Builder
+	for _, r := range line[:offset] {
+		if r == '\t' {
+			caret.WriteRune('\t')
+		} else {
+			caret.WriteRune(' ')
+		}
+	}
+	caret.WriteRune('^')
+
+	return line + "\n" + caret.String() + "\n"
+}
+
 type Parser struct {
 	lexer  *lexer.Lexer
 	logger *slog.Logger
@@ -28,16 +85,255 @@ type Parser struct {
 	curToken  token.Token
 	peekToken token.Token
 
+	curComments  []token.Token
+	peekComments []token.Token
+
+	// peekOffset is the byte offset of the first token or comment after
+	// curToken.
+	peekOffset int
+
+	// curTrivia and peekTrivia are the source text before curToken and
+	// peekToken, only recorded with WithTrivia.
+	curTrivia  string
+	peekTrivia string
+
 	parseFnMap map[token.TokenType]parseFn
 
+	depth    int
+	maxDepth int
+
+	maxElements int
+
+	allowEmptyInput bool
+	bigNumbers      bool
+	extendedEscapes bool
+	braceUnicode    bool
+	detectIntegers  bool
+	clampNumbers    bool
+	allowUndefined  bool
+	leadingPlus     bool
+	allowScalarRoot bool
+	allowedRoots    []token.TokenType
+	parentLinks     bool
+	trivia          bool
+	lintNumbers     bool
+
+	warnings []Warning
+
+	// interned maps the text of every key parsed with WithStringInterning
+	// to the copy shared by all keys with that text.
+	interned map[string]string
+
+	skipPreamble func(line string) bool
+
+	// tokens holds every token read from the lexer when recordTokens is
+	// set, see ParseWithTokens.
+	recordTokens bool
+	tokens       []token.Token
+
+	duplicateKeys DuplicateKeyPolicy
+	// reportDuplicates is set while ParseFileReportingDuplicates runs.
+	reportDuplicates bool
+	// duplicates holds the repeated keys found while reportDuplicates is
+	// set.
+	duplicates []DuplicateReport
+
 	JSONErr *JSONErr
 }
 
-func New(l *lexer.Lexer) *Parser {
+// DuplicateKeyPolicy decides what happens when an object repeats a key.
+type DuplicateKeyPolicy int
+
+const (
+	// DuplicateKeysError rejects the document. This is the default.
+	DuplicateKeysError DuplicateKeyPolicy = iota
+	// DuplicateKeysFirst keeps the value of the first occurrence.
+	DuplicateKeysFirst
+	// DuplicateKeysLast keeps the value of the last occurrence, like
+	// encoding/json does.
+	DuplicateKeysLast
+)
+
+// DuplicateReport describes a key repeated within one object, found by
+// ParseFileReportingDuplicates.
+type DuplicateReport struct {
+	Key       string
+	First     token.Position
+	Duplicate token.Position
+}
+
+type Option func(*Parser)
+
+// WithMaxDepth limits how deeply objects and arrays may be nested. A limit
+// of zero or less disables the check.
+func WithMaxDepth(n int) Option {
+	return func(p *Parser) {
+		p.maxDepth = n
+	}
+}
+
+// WithMaxElements limits how many pairs a single object and how many
+// elements a single array may hold. A limit of zero or less disables the
+// check.
+func WithMaxElements(n int) Option {
+	return func(p *Parser) {
+		p.maxElements = n
+	}
+}
+
+// WithDuplicateKeys sets how objects that repeat a key are handled. The
+// key keeps the position of its first occurrence either way.
+func WithDuplicateKeys(policy DuplicateKeyPolicy) Option {
+	return func(p *Parser) {
+		p.duplicateKeys = policy
+	}
+}
+
+// AllowEmptyInput makes ParseFile return an empty JSONFile instead of an
+// error when the input is empty or only contains whitespace.
+func AllowEmptyInput() Option {
+	return func(p *Parser) {
+		p.allowEmptyInput = true
+	}
+}
+
+// WithBigNumbers makes parseNumber keep the exact value of every number as
+// a *big.Rat, which ToInterface then returns instead of a float64.
+func WithBigNumbers() Option {
+	return func(p *Parser) {
+		p.bigNumbers = true
+	}
+}
+
+// AllowExtendedEscapes accepts the non-standard \xHH and \0 string escapes
+// emitted by some JavaScript tools. StringLiteral.Unescaped decodes them.
+func AllowExtendedEscapes() Option {
+	return func(p *Parser) {
+		p.extendedEscapes = true
+	}
+}
+
+// AllowBraceUnicode accepts the ECMAScript \u{XXXX} string escape of 1 to
+// 6 hex digits, e.g. \u{1F600}, emitted by some non-standard producers.
+// StringLiteral.Unescaped decodes it.
+func AllowBraceUnicode() Option {
+	return func(p *Parser) {
+		p.braceUnicode = true
+	}
+}
+
+// ClampNumbers makes parseNumber turn numbers beyond the float64 range into
+// ±math.MaxFloat64 instead of reporting an error.
+func ClampNumbers() Option {
+	return func(p *Parser) {
+		p.clampNumbers = true
+	}
+}
+
+// WithParentLinks sets the Parent of every parsed node, so ast.Parent and
+// ast.KeyOf can navigate upwards from it.
+func WithParentLinks() Option {
+	return func(p *Parser) {
+		p.parentLinks = true
+	}
+}
+
+// WithTrivia records the whitespace and comments around every node in its
+// ast.Trivia, so ast.Marshaler with PreserveTrivia can reproduce the input
+// byte for byte, e.g. to edit a file without changing its formatting.
+func WithTrivia() Option {
+	return func(p *Parser) {
+		p.trivia = true
+	}
+}
+
+// LintNumbers records a Warning for every number that is valid but not
+// written in its plainest form, e.g. 1.0, 1e2, 1E5 or 1e+5. They are
+// returned by Warnings and do not stop parsing.
+func LintNumbers() Option {
+	return func(p *Parser) {
+		p.lintNumbers = true
+	}
+}
+
+// WithStringInterning makes identical object keys share a single copy of
+// their text. Keys normally point into the input, so holding on to them,
+// e.g. in an index built from many records, keeps the whole input alive.
+// The copies are kept across Reset, so records parsed with the same parser
+// share them too.
+func WithStringInterning() Option {
+	return func(p *Parser) {
+		p.interned = make(map[string]string)
+	}
+}
+
+// SkipPreamble discards the leading lines of the input for which skip
+// returns true before parsing, e.g. a header written by a logging tool.
+// Reported positions still count the skipped lines.
+func SkipPreamble(skip func(line string) bool) Option {
+	return func(p *Parser) {
+		p.skipPreamble = skip
+	}
+}
+
+// WithIntegerDetection makes ToInterface return numbers written without a
+// fraction or exponent as int64, e.g. 42 but not 42.0, as long as they fit.
+// Other numbers stay float64, or *big.Rat with WithBigNumbers.
+func WithIntegerDetection() Option {
+	return func(p *Parser) {
+		p.detectIntegers = true
+	}
+}
+
+// AllowUndefined accepts the JavaScript `undefined` keyword as a value and
+// parses it as null.
+func AllowUndefined() Option {
+	return func(p *Parser) {
+		p.allowUndefined = true
+	}
+}
+
+// AllowLeadingPlus accepts numbers with an explicit positive sign such as
+// +123 and +1.5e2. New and Reset turn on lexer.AllowLeadingPlus in the
+// lexer they are given, which keeps accepting them once the parser is done
+// with it; ParseString and the other helpers create their own lexer.
+func AllowLeadingPlus() Option {
+	return func(p *Parser) {
+		p.leadingPlus = true
+	}
+}
+
+// AllowScalarRoot accepts a string, number, boolean or null as the root
+// value, as RFC 8259 does, instead of only an object or an array.
+func AllowScalarRoot() Option {
+	return func(p *Parser) {
+		p.allowScalarRoot = true
+	}
+}
+
+// WithAllowedRoots only accepts a root value that starts with one of
+// types, e.g. token.LBRACE for an API that takes nothing but an object,
+// and names what is required in the error otherwise:
+//
+//	This input requires a JSON object at the root, got '[' instead
+//
+// Scalar types such as token.STRING may be listed too. token.TRUE and
+// token.FALSE both mean a boolean, so either one accepts true and false.
+func WithAllowedRoots(types ...token.TokenType) Option {
+	return func(p *Parser) {
+		p.allowedRoots = types
+	}
+}
+
+// New returns a parser reading from l with opts applied. With
+// AllowLeadingPlus, l is changed to accept numbers such as +123.
+func New(l *lexer.Lexer, opts ...Option) *Parser {
 	p := &Parser{
-		logger:  l.Logger,
-		lexer:   l,
-		JSONErr: &JSONErr{},
+		maxDepth: DefaultMaxDepth,
+	}
+
+	for _, opt := range opts {
+		opt(p)
 	}
 
 	p.parseFnMap = make(map[token.TokenType]parseFn)
@@ -45,52 +341,403 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerElement(token.TRUE, p.parseBoolean)
 	p.registerElement(token.FALSE, p.parseBoolean)
 	p.registerElement(token.NULL, p.parseNull)
+	if p.allowUndefined {
+		p.registerElement(token.UNDEFINED, p.parseUndefined)
+	}
 	p.registerElement(token.NUMBER, p.parseNumber)
 	p.registerElement(token.LBRACKET, p.parseArray)
 	p.registerElement(token.LBRACE, p.parseObject)
 
+	p.Reset(l)
+
+	return p
+}
+
+// ParseString parses s without logging. It is a shortcut for creating a
+// lexer and a parser and calling ParseFile.
+func ParseString(s string, opts ...Option) (*ast.JSONFile, *JSONErr) {
+	return New(lexer.New(nil, s), opts...).ParseFile()
+}
+
+// ParseBytes is like ParseString for a byte slice.
+func ParseBytes(b []byte, opts ...Option) (*ast.JSONFile, *JSONErr) {
+	return ParseString(string(b), opts...)
+}
+
+// ParseToInterface parses s like ParseString and returns its root as
+// ToInterface does, e.g. a map[string]interface{} for an object, like
+// encoding/json would.
+func ParseToInterface(s string, opts ...Option) (interface{}, *JSONErr) {
+	jf, err := ParseString(s, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return jf.ToInterface(), nil
+}
+
+// ParseObject parses s like ParseString and returns its root, reporting an
+// error when the root is not an object.
+func ParseObject(s string, opts ...Option) (*ast.Object, *JSONErr) {
+	root, err := parseRoot(s, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, ok := root.(*ast.Object)
+	if !ok {
+		return nil, rootTypeError("object", root)
+	}
+	return obj, nil
+}
+
+// ParseArray is like ParseObject for a root array.
+func ParseArray(s string, opts ...Option) (*ast.ArrayLiteral, *JSONErr) {
+	root, err := parseRoot(s, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	arr, ok := root.(*ast.ArrayLiteral)
+	if !ok {
+		return nil, rootTypeError("array", root)
+	}
+	return arr, nil
+}
+
+// parseRoot returns the root of s, or nil when s is empty and that is
+// allowed by AllowEmptyInput.
+func parseRoot(s string, opts ...Option) (ast.Element, *JSONErr) {
+	jf, err := ParseString(s, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if len(jf.Elements) == 0 {
+		return nil, nil
+	}
+	return jf.Elements[0], nil
+}
+
+func rootTypeError(expected string, root ast.Element) *JSONErr {
+	got, pos := "empty input", token.Position{Line: 1, Column: 1}
+	if root != nil {
+		got, pos = ast.TypeName(root), elementPosition(root)
+	}
+
+	msg := fmt.Sprintf("Expected %s at root, got %s\n", expected, got)
+	return &JSONErr{Msg: msg, Pos: pos}
+}
+
+// elementPosition returns where e starts in the input.
+func elementPosition(e ast.Element) token.Position {
+	switch e := e.(type) {
+	case *ast.Object:
+		return e.Token.Position
+	case *ast.ArrayLiteral:
+		return e.Token.Position
+	case *ast.StringLiteral:
+		return e.Token.Position
+	case *ast.NumberLiteral:
+		return e.Token.Position
+	case *ast.Boolean:
+		return e.Token.Position
+	case *ast.Null:
+		return e.Token.Position
+	}
+	return token.Position{Line: 1, Column: 1}
+}
+
+// Reset makes p parse the input of l, keeping its options but dropping
+// every token, error, warning and duplicate report left from the previous
+// document. Keys interned with WithStringInterning stay shared. With
+// AllowLeadingPlus, l is changed to accept numbers such as +123.
+func (p *Parser) Reset(l *lexer.Lexer) {
+	p.lexer = l
+	p.logger = l.Logger
+	if p.leadingPlus {
+		lexer.AllowLeadingPlus()(l)
+	}
+	if p.skipPreamble != nil {
+		l.SkipLines(p.skipPreamble)
+	}
+	p.JSONErr = &JSONErr{}
+	p.depth = 0
+
+	p.prvToken = token.Token{}
+	p.curToken = token.Token{}
+	p.peekToken = token.Token{}
+	p.peekOffset = 0
+	p.curComments = nil
+	p.peekComments = nil
+	p.curTrivia = ""
+	p.peekTrivia = ""
+	p.warnings = nil
+	p.duplicates = nil
+	p.tokens = nil
+
 	p.nextToken()
 	p.nextToken()
+}
 
-	return p
+// Current returns the token the parser is on without advancing.
+func (p *Parser) Current() token.Token {
+	return p.curToken
+}
+
+// Peek returns the token after Current without consuming it, so
+// extensions can look ahead before deciding how to parse.
+func (p *Parser) Peek() token.Token {
+	return p.peekToken
+}
+
+// logging reports whether the logger writes Info records. Log calls check
+// it first so their attributes are not built when logging is off.
+func (p *Parser) logging() bool {
+	return p.logger.Enabled(context.Background(), slog.LevelInfo)
 }
 
 func (p *Parser) ParseFile() (*ast.JSONFile, *JSONErr) {
-	p.logger.Info("Parsing File:",
-		"currentToken", p.curToken.Literal,
-		"currentTokenType", p.curToken.Type,
-	)
+	if p.logging() {
+		p.logger.Info("Parsing File:",
+			"currentToken", p.curToken.Literal,
+			"currentTokenType", p.curToken.Type,
+		)
+	}
 
 	jf := &ast.JSONFile{}
 	jf.Elements = []ast.Element{}
 
-	if !p.curTokenIs(token.LBRACE) && !p.curTokenIs(token.LBRACKET) {
-		msg := fmt.Sprintf("Expected '{' or '[', got '%+v' instead\n", p.curToken.Type)
-		return nil, &JSONErr{Msg: msg, Pos: p.curToken.Position}
+	if p.allowEmptyInput && p.curTokenIs(token.EOF) {
+		if p.logging() {
+			p.logger.Info("Parsing File Complete, Empty Input:")
+		}
+		return jf, nil
+	}
+
+	if err := p.checkRoot(); err != nil {
+		return nil, err
+	}
+
+	leading := p.curComments
+	before := p.curTrivia
+
+	elem, err := p.parseElement()
+	if err != nil {
+		if p.logging() {
+			p.logger.Info("Parsing File Stopped:", "jsonErr", err)
+		}
+		return nil, err
+	}
+	ast.AddLeadingComments(elem, commentLiterals(leading)...)
+	p.setTrivia(elem, before)
+	if p.parentLinks {
+		ast.LinkParents(elem)
+	}
+
+	jf.Elements = append(jf.Elements, elem)
+	if p.logging() {
+		p.logger.Info("Adding Element to Elements", "elem", elem.String())
+	}
+
+	p.nextToken()
+
+	if err := p.trailingContentError(); err != nil {
+		return nil, err
 	}
+	ast.AddTrailingComments(elem, commentLiterals(p.curComments)...)
+	p.setTrailingTrivia(elem)
+
+	if p.logging() {
+		p.logger.Info("Parsing File Complete:", "jsonFile", jf.String())
+	}
+	return jf, nil
+}
+
+// ParseFileReportingDuplicates parses like ParseFile with the
+// DuplicateKeysLast policy, whatever policy the parser was created with,
+// and reports every repeated key in the order they were found, e.g. to
+// audit documents from a producer that should not repeat keys.
+func (p *Parser) ParseFileReportingDuplicates() ([]DuplicateReport, *ast.JSONFile, *JSONErr) {
+	policy := p.duplicateKeys
+	p.duplicateKeys = DuplicateKeysLast
+	p.reportDuplicates = true
+	defer func() {
+		p.duplicateKeys = policy
+		p.reportDuplicates = false
+	}()
+
+	jf, err := p.ParseFile()
+	if err != nil {
+		return nil, nil, err
+	}
+	return p.duplicates, jf, nil
+}
+
+// ParseConcatenated parses JSON values that follow each other with nothing
+// but optional whitespace in between, e.g. `{"a":1}{"b":2}`, and returns one
+// JSONFile per value.
+func ParseConcatenated(l *lexer.Lexer, opts ...Option) ([]*ast.JSONFile, *JSONErr) {
+	p := New(l, opts...)
+	docs := []*ast.JSONFile{}
 
 	for !p.curTokenIs(token.EOF) {
+		if err := p.checkRoot(); err != nil {
+			return nil, err
+		}
+
+		leading := p.curComments
+
 		elem, err := p.parseElement()
 		if err != nil {
-			p.logger.Info("Parsing File Stopped:", "jsonErr", err)
+			if p.logging() {
+				p.logger.Info("Parsing Concatenated Stopped:", "jsonErr", err)
+			}
 			return nil, err
 		}
+		ast.AddLeadingComments(elem, commentLiterals(leading)...)
+		if p.parentLinks {
+			ast.LinkParents(elem)
+		}
 
-		jf.Elements = append(jf.Elements, elem)
-		p.logger.Info("Adding Element to Elements", "elem", elem.String())
+		docs = append(docs, &ast.JSONFile{Elements: []ast.Element{elem}})
+		if p.logging() {
+			p.logger.Info("Adding Concatenated Document", "elem", elem.String())
+		}
 
 		p.nextToken()
+
+		if p.curTokenIs(token.EOF) {
+			ast.AddTrailingComments(elem, commentLiterals(p.curComments)...)
+		}
 	}
 
-	p.logger.Info("Parsing File Complete:", "jsonFile", jf.String())
-	return jf, nil
+	return docs, nil
+}
+
+// ParseWithTokens parses the input of l like ParseFile and also returns
+// every token read from l on the way, comments included, ending with EOF.
+// Tools that need both the tree and the token positions can use it instead
+// of lexing the input a second time. When parsing fails, the tokens read
+// up to the error are returned with it.
+func ParseWithTokens(l *lexer.Lexer, opts ...Option) (*ast.JSONFile, []token.Token, *JSONErr) {
+	record := func(p *Parser) { p.recordTokens = true }
+	p := New(l, append([]Option{record}, opts...)...)
+
+	jf, err := p.ParseFile()
+	if err != nil {
+		return nil, p.tokens, err
+	}
+	return jf, p.tokens, nil
+}
+
+// ParseValueAndRest parses a single object or array and returns it with
+// the byte offset just past it and any whitespace that follows, so the
+// caller can continue with the rest of the input. Unlike ParseFile it does
+// not require the value to be followed by EOF. Calling it again parses the
+// next value.
+func (p *Parser) ParseValueAndRest() (ast.Element, int, *JSONErr) {
+	if err := p.checkRoot(); err != nil {
+		return nil, 0, err
+	}
+
+	leading := p.curComments
+
+	elem, err := p.parseElement()
+	if err != nil {
+		if p.logging() {
+			p.logger.Info("Parsing Value Stopped:", "jsonErr", err)
+		}
+		return nil, 0, err
+	}
+	ast.AddLeadingComments(elem, commentLiterals(leading)...)
+	if p.parentLinks {
+		ast.LinkParents(elem)
+	}
+
+	rest := p.peekOffset
+	p.nextToken()
+
+	if p.logging() {
+		p.logger.Info("Parsing Value Completed:", "elem", elem.String(), "rest", rest)
+	}
+	return elem, rest, nil
+}
+
+func (p *Parser) checkRoot() *JSONErr {
+	if p.curTokenIs(token.EOF) {
+		return &JSONErr{Msg: "Unexpected end of input, expected a JSON value\n", Pos: p.curToken.Position}
+	}
+	if len(p.allowedRoots) > 0 {
+		if !p.rootAllowed(p.curToken.Type) || p.parseFnMap[p.curToken.Type] == nil {
+			if err := p.illegalTokenError(p.curToken); err != nil {
+				return err
+			}
+			msg := fmt.Sprintf("This input requires a JSON %s at the root, got '%+v' instead\n",
+				rootNames(p.allowedRoots), p.curToken.Type)
+			return &JSONErr{Msg: msg, Pos: p.curToken.Position}
+		}
+		return nil
+	}
+	if p.allowScalarRoot {
+		if p.parseFnMap[p.curToken.Type] == nil {
+			return p.noParseFnError(p.curToken)
+		}
+		return nil
+	}
+	if !p.curTokenIs(token.LBRACE) && !p.curTokenIs(token.LBRACKET) {
+		if err := p.illegalTokenError(p.curToken); err != nil {
+			return err
+		}
+		msg := fmt.Sprintf("Expected '{' or '[', got '%+v' instead\n", p.curToken.Type)
+		return &JSONErr{Msg: msg, Pos: p.curToken.Position}
+	}
+	return nil
+}
+
+// rootAllowed reports whether a root value starting with t is one of the
+// allowed roots. token.TRUE and token.FALSE both stand for a boolean, so
+// listing either accepts the other.
+func (p *Parser) rootAllowed(t token.TokenType) bool {
+	switch t {
+	case token.TRUE, token.FALSE:
+		return slices.Contains(p.allowedRoots, token.TRUE) || slices.Contains(p.allowedRoots, token.FALSE)
+	}
+	return slices.Contains(p.allowedRoots, t)
+}
+
+// rootNames describes the values that start with types, e.g. "object or
+// array".
+func rootNames(types []token.TokenType) string {
+	names := []string{}
+	for _, t := range types {
+		name := string(t)
+		switch t {
+		case token.LBRACE:
+			name = "object"
+		case token.LBRACKET:
+			name = "array"
+		case token.STRING:
+			name = "string"
+		case token.NUMBER:
+			name = "number"
+		case token.TRUE, token.FALSE:
+			name = "boolean"
+		case token.NULL:
+			name = "null"
+		}
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, " or ")
 }
 
 func (p *Parser) parseElement() (ast.Element, *JSONErr) {
-	p.logger.Info("Parsing Element:",
-		"currentToken", p.curToken.Literal,
-		"currentTokenType", p.curToken.Type,
-	)
+	if p.logging() {
+		p.logger.Info("Parsing Element:",
+			"currentToken", p.curToken.Literal,
+			"currentTokenType", p.curToken.Type,
+		)
+	}
 
 	switch p.curToken.Type {
 	case token.LBRACE:
@@ -98,24 +745,79 @@ func (p *Parser) parseElement() (ast.Element, *JSONErr) {
 	case token.LBRACKET:
 		return p.parseArray()
 	default:
-		msg := fmt.Sprintf("Expected 'EOF', got '%+v' instead\n", p.curToken.Type)
-		p.logger.Info("Illegal TokenType:",
+		scalarRoot := p.allowScalarRoot || len(p.allowedRoots) > 0
+		if parseFn := p.parseFnMap[p.curToken.Type]; scalarRoot && parseFn != nil {
+			return parseFn()
+		}
+		msg := fmt.Sprintf("Expected '{' or '[', got '%+v' instead\n", p.curToken.Type)
+		return nil, &JSONErr{Msg: msg, Pos: p.curToken.Position}
+	}
+}
+
+// trailingContentError reports anything but EOF after the root value,
+// pointing at the start of the first trailing token.
+func (p *Parser) trailingContentError() *JSONErr {
+	if p.curTokenIs(token.EOF) {
+		return nil
+	}
+
+	if p.logging() {
+		p.logger.Info("Trailing Content:",
 			"currentToken", p.curToken.Literal,
 			"currentTokenType", p.curToken.Type,
-			"jsonError", p.JSONErr,
 		)
-		return nil, &JSONErr{Msg: msg, Pos: p.peekToken.Position}
 	}
+	return &JSONErr{Msg: "Unexpected trailing content after JSON value\n", Pos: p.curToken.Position}
+}
+
+// keyText returns the unescaped text of an object key, used to find
+// duplicates. A key with an invalid escape is compared as written.
+func keyText(key ast.Element) string {
+	if sl, ok := key.(*ast.StringLiteral); ok {
+		if str, err := sl.Unescaped(); err == nil {
+			return str
+		}
+	}
+	return key.String()
 }
 
 func (p *Parser) parseObject() (ast.Element, *JSONErr) {
+	if err := p.enterNested(); err != nil {
+		return nil, err
+	}
+	defer p.leaveNested()
+
 	obj := &ast.Object{Token: p.curToken}
-	p.logger.Info("Parsing Object:",
-		"currentToken", p.curToken.Literal,
-		"currentTokenType", p.curToken.Type,
-	)
+	if p.logging() {
+		p.logger.Info("Parsing Object:",
+			"currentToken", p.curToken.Literal,
+			"currentTokenType", p.curToken.Type,
+		)
+	}
 
 	obj.Pairs = make(map[ast.Element]ast.Element)
+	start := p.curToken.Position
+
+	// seen maps the unescaped text of every key parsed so far to its node,
+	// so duplicates are found without scanning Pairs and "a" matches
+	// "\u0061".
+	seen := make(map[string]ast.Element)
+
+	if err := p.unexpectedEOFError("object", start); err != nil {
+		return nil, err
+	}
+
+	if err := p.illegalTokenError(p.peekToken); err != nil {
+		return nil, err
+	}
+
+	if err := p.nonStringKeyError(p.peekToken); err != nil {
+		return nil, err
+	}
+
+	if err := p.unquotedKeyError(p.peekToken); err != nil {
+		return nil, err
+	}
 
 	if !p.peekTokenIs(token.STRING) && !p.peekTokenIs(token.RBRACE) {
 		msg := fmt.Sprintf("Expected 'STRING', '}', got '%+v' instead\n", p.peekToken.Type)
@@ -123,35 +825,114 @@ func (p *Parser) parseObject() (ast.Element, *JSONErr) {
 	}
 
 	for !p.peekTokenIs(token.RBRACE) {
+		if err := p.tooManyElements("object", len(obj.Keys)); err != nil {
+			return nil, err
+		}
+
 		p.nextToken()
 
 		prop, err := p.parseValue()
 		if err != nil {
 			return nil, err
 		}
+		p.intern(prop)
 
-		if err := p.expectPeek(token.COLON); err != nil {
+		if err := p.unexpectedEOFError("object", start); err != nil {
 			return nil, err
 		}
 
-		if p.isDuplicateProperty(obj.Pairs, prop) {
+		if !p.peekTokenIs(token.COLON) {
+			msg := fmt.Sprintf("Expected ':' after object key '%s', got '%v' instead\n",
+				prop.TokenLiteral(), p.peekToken.Type)
+			return nil, &JSONErr{Msg: msg, Pos: p.peekToken.Position}
+		}
+		p.nextToken()
+		ast.AddTrailingComments(prop, commentLiterals(p.curComments)...)
+		p.setTrailingTrivia(prop)
+
+		existing := seen[keyText(prop)]
+		if existing != nil && p.duplicateKeys == DuplicateKeysError {
 			msg := fmt.Sprintf("Duplicate JSON property '%+v'\n", prop)
 			return nil, &JSONErr{Msg: msg, Pos: p.curToken.Position}
 		}
+		if existing != nil && p.reportDuplicates {
+			p.duplicates = append(p.duplicates, DuplicateReport{
+				Key:       prop.TokenLiteral(),
+				First:     existing.(*ast.StringLiteral).Token.Position,
+				Duplicate: prop.(*ast.StringLiteral).Token.Position,
+			})
+		}
+
+		if err := p.unexpectedEOFError("object", start); err != nil {
+			return nil, err
+		}
 
 		p.nextToken()
 
+		if p.curTokenIs(token.RBRACE) || p.curTokenIs(token.COMMA) {
+			msg := fmt.Sprintf("Missing value for key '%s'\n", prop.TokenLiteral())
+			return nil, &JSONErr{Msg: msg, Pos: p.curToken.Position}
+		}
+
 		val, err := p.parseValue()
 		if err != nil {
 			return nil, err
 		}
-		obj.Pairs[prop] = val
+		switch {
+		case existing == nil:
+			obj.Pairs[prop] = val
+			obj.Keys = append(obj.Keys, prop)
+			seen[keyText(prop)] = prop
+		case p.duplicateKeys == DuplicateKeysLast:
+			if p.logging() {
+				p.logger.Info("Replacing Duplicate Property:", "key", prop.String())
+			}
+			obj.Pairs[existing] = val
+		default:
+			if p.logging() {
+				p.logger.Info("Ignoring Duplicate Property:", "key", prop.String())
+			}
+		}
+
+		if err := p.unexpectedEOFError("object", start); err != nil {
+			return nil, err
+		}
+
+		if !p.peekTokenIs(token.COMMA) && !p.peekTokenIs(token.RBRACE) {
+			if err := p.illegalTokenError(p.peekToken); err != nil {
+				return nil, err
+			}
+			msg := fmt.Sprintf("Expected ',' or '}' after value, got '%v' instead\n", p.peekToken.Type)
+			return nil, &JSONErr{Msg: msg, Pos: p.peekToken.Position}
+		}
+		if p.peekTokenIs(token.COMMA) {
+			p.nextToken()
+		}
+
+		if p.curTokenIs(token.COMMA) {
+			p.attachCommaComments(val)
+			p.setTrailingTrivia(val)
+		}
+
+		if err := p.unexpectedEOFError("object", start); err != nil {
+			return nil, err
+		}
 
-		if err := p.expectPeek(token.COMMA); !p.peekTokenIs(token.RBRACE) && err != nil {
+		if err := p.illegalTokenError(p.peekToken); err != nil {
 			return nil, err
 		}
 
+		if p.curTokenIs(token.COMMA) && p.peekTokenIs(token.RBRACE) {
+			return nil, p.trailingCommaError(p.peekToken)
+		}
+
 		if p.curTokenIs(token.COMMA) && !p.peekTokenIs(token.STRING) {
+			if err := p.nonStringKeyError(p.peekToken); err != nil {
+				return nil, err
+			}
+			if err := p.unquotedKeyError(p.peekToken); err != nil {
+				return nil, err
+			}
 			msg := fmt.Sprintf("Expected 'STRING', got '%v' instead\n", p.peekToken.Type)
 			return nil, &JSONErr{Msg: msg, Pos: p.peekToken.Position}
 		}
@@ -160,37 +941,58 @@ func (p *Parser) parseObject() (ast.Element, *JSONErr) {
 	if err := p.expectPeek(token.RBRACE); err != nil {
 		return nil, err
 	}
+	p.setClosingTrivia(obj)
+
+	if len(obj.Keys) > 0 {
+		ast.AddTrailingComments(obj.Pairs[obj.Keys[len(obj.Keys)-1]], commentLiterals(p.curComments)...)
+	} else {
+		ast.AddTrailingComments(obj, commentLiterals(p.curComments)...)
+	}
 
 	return obj, nil
 }
 
 func (p *Parser) parseValue() (ast.Element, *JSONErr) {
+	leading := p.curComments
+	before := p.curTrivia
 	parseFn := p.parseFnMap[p.curToken.Type]
-	p.logger.Info("Parsing Value:",
-		"currentToken", p.curToken.Literal,
-		"currentTokenType", p.curToken.Type,
-		"jsonError", p.JSONErr,
-	)
+	if p.logging() {
+		p.logger.Info("Parsing Value:",
+			"currentToken", p.curToken.Literal,
+			"currentTokenType", p.curToken.Type,
+			"jsonError", p.JSONErr,
+		)
+	}
 
 	if parseFn == nil {
-		p.logger.Info("Exiting Parsing Value, No Parsing Function Found:")
+		if p.logging() {
+			p.logger.Info("Exiting Parsing Value, No Parsing Function Found:")
+		}
 		err := p.noParseFnError(p.curToken)
 		return nil, err
 	}
 
 	val, err := parseFn()
 	if err != nil {
-		p.logger.Info("Parsing Value Stopped:", "jsonErr", err)
+		if p.logging() {
+			p.logger.Info("Parsing Value Stopped:", "jsonErr", err)
+		}
 		return nil, err
 	}
+	ast.AddLeadingComments(val, commentLiterals(leading)...)
+	p.setTrivia(val, before)
 
-	p.logger.Info("Parsing Value Completed:", "value", val.String())
+	if p.logging() {
+		p.logger.Info("Parsing Value Completed:", "value", val.String())
+	}
 	return val, nil
 }
 
 func (p *Parser) parseString() (ast.Element, *JSONErr) {
 	str := p.curToken.Literal
-	p.logger.Info("Parsing String:", "string", str)
+	if p.logging() {
+		p.logger.Info("Parsing String:", "string", str)
+	}
 
 	for i := 0; i < len(str); i++ {
 		r := str[i]
@@ -201,11 +1003,18 @@ func (p *Parser) parseString() (ast.Element, *JSONErr) {
 				i += escLen - 1
 				continue
 			}
-			p.logger.Info("Parsing String Stopped:", "escLen", escLen)
+			if p.logging() {
+				p.logger.Info("Parsing String Stopped:", "escLen", escLen)
+			}
 			return nil, err
 		}
 	}
-	return &ast.StringLiteral{Token: p.curToken, Value: p.curToken.Literal}, nil
+	return &ast.StringLiteral{
+		Token:           p.curToken,
+		Value:           p.curToken.Literal,
+		ExtendedEscapes: p.extendedEscapes,
+		BraceUnicode:    p.braceUnicode,
+	}, nil
 }
 
 func (p *Parser) parseBoolean() (ast.Element, *JSONErr) {
@@ -215,6 +1024,15 @@ func (p *Parser) parseBoolean() (ast.Element, *JSONErr) {
 	}, nil
 }
 
+// parseUndefined returns a Null that keeps the `undefined` token, so
+// positions stay accurate while marshaling still writes null.
+func (p *Parser) parseUndefined() (ast.Element, *JSONErr) {
+	return &ast.Null{
+		Token: p.curToken,
+		Value: "null",
+	}, nil
+}
+
 func (p *Parser) parseNull() (ast.Element, *JSONErr) {
 	return &ast.Null{
 		Token: p.curToken,
@@ -223,55 +1041,160 @@ func (p *Parser) parseNull() (ast.Element, *JSONErr) {
 }
 
 func (p *Parser) parseNumber() (ast.Element, *JSONErr) {
-	num := &ast.NumberLiteral{Token: p.curToken}
-	p.logger.Info("Parsing Number:", "num", num)
+	num := &ast.NumberLiteral{Token: p.curToken, DetectIntegers: p.detectIntegers}
+	if p.logging() {
+		p.logger.Info("Parsing Number:", "num", num)
+	}
+
+	if num.IsHex() {
+		return p.parseHexNumber(num)
+	}
+
+	if p.lintNumbers {
+		p.lintNumber(p.curToken)
+	}
+
+	if p.bigNumbers {
+		return p.parseBigNumber(num)
+	}
 
+	// Numbers too small for a float64 silently become zero, only those too
+	// large are reported as out of range.
 	value, err := strconv.ParseFloat(p.curToken.Literal, 64)
-	if err != nil {
+	if errors.Is(err, strconv.ErrRange) {
+		if !p.clampNumbers {
+			msg := fmt.Sprintf("Number %q overflows float64 range\n", p.curToken.Literal)
+			return nil, &JSONErr{Msg: msg, Pos: p.curToken.Position}
+		}
+		value = math.Copysign(math.MaxFloat64, value)
+	} else if err != nil {
 		msg := fmt.Sprintf("Failed parsing %q as a float\n", p.curToken.Literal)
 		return nil, &JSONErr{Msg: msg, Pos: p.curToken.Position}
 	}
 
 	num.Value = value
 
-	p.logger.Info("Parsing Number Completed:", "num", num)
+	if p.logging() {
+		p.logger.Info("Parsing Number Completed:", "num", num)
+	}
+	return num, nil
+}
+
+// maxBigExponent bounds the exponent of numbers parsed with WithBigNumbers,
+// since an exact value for 1e999999999 would need gigabytes of memory.
+const maxBigExponent = 10000
+
+func (p *Parser) parseBigNumber(num *ast.NumberLiteral) (ast.Element, *JSONErr) {
+	literal := p.curToken.Literal
+
+	if i := strings.IndexAny(literal, "eE"); i >= 0 {
+		exp, err := strconv.Atoi(literal[i+1:])
+		if err != nil || exp > maxBigExponent || exp < -maxBigExponent {
+			msg := fmt.Sprintf("Exponent of %q is out of range for big numbers\n", literal)
+			return nil, &JSONErr{Msg: msg, Pos: p.curToken.Position}
+		}
+	}
+
+	value, ok := new(big.Rat).SetString(literal)
+	if !ok {
+		msg := fmt.Sprintf("Failed parsing %q as a big number\n", literal)
+		return nil, &JSONErr{Msg: msg, Pos: p.curToken.Position}
+	}
+
+	num.Big = value
+	num.Value, _ = value.Float64()
+
+	if p.logging() {
+		p.logger.Info("Parsing Big Number Completed:", "num", num)
+	}
+	return num, nil
+}
+
+// parseHexNumber converts the hexadecimal integers produced by a lexer
+// created with lexer.AllowHexNumbers.
+func (p *Parser) parseHexNumber(num *ast.NumberLiteral) (ast.Element, *JSONErr) {
+	literal := p.curToken.Literal
+
+	n, ok := new(big.Int).SetString(literal, 0)
+	if !ok {
+		msg := fmt.Sprintf("Failed parsing %q as a hex number\n", literal)
+		return nil, &JSONErr{Msg: msg, Pos: p.curToken.Position}
+	}
+
+	if p.bigNumbers {
+		num.Big = new(big.Rat).SetInt(n)
+	}
+	num.Value, _ = new(big.Float).SetInt(n).Float64()
+
+	if p.logging() {
+		p.logger.Info("Parsing Hex Number Completed:", "num", num)
+	}
 	return num, nil
 }
 
 func (p *Parser) parseArray() (ast.Element, *JSONErr) {
+	if err := p.enterNested(); err != nil {
+		return nil, err
+	}
+	defer p.leaveNested()
+
 	array := &ast.ArrayLiteral{Token: p.curToken}
-	p.logger.Info("Parsing Array Started:")
+	if p.logging() {
+		p.logger.Info("Parsing Array Started:")
+	}
 
 	var err *JSONErr
 	array.Elements, err = p.parseArrayList(token.RBRACKET)
 	if err != nil {
-		p.logger.Info("Parsing Array Stopped:", "jsonError", err)
+		if p.logging() {
+			p.logger.Info("Parsing Array Stopped:", "jsonError", err)
+		}
 		return nil, err
 	}
+	p.setClosingTrivia(array)
 
-	p.logger.Info("Parsing Array Completed:",
-		"array", array.Elements,
-		"currentToken", array.Token.Literal,
-		"currentTokenType", array.Token.Type,
-	)
+	if len(array.Elements) > 0 {
+		ast.AddTrailingComments(array.Elements[len(array.Elements)-1], commentLiterals(p.curComments)...)
+	} else {
+		ast.AddTrailingComments(array, commentLiterals(p.curComments)...)
+	}
+
+	if p.logging() {
+		p.logger.Info("Parsing Array Completed:",
+			"array", array.Elements,
+			"currentToken", array.Token.Literal,
+			"currentTokenType", array.Token.Type,
+		)
+	}
 	return array, nil
 }
 
 func (p *Parser) parseArrayList(end token.TokenType) ([]ast.Element, *JSONErr) {
 	list := []ast.Element{}
-	p.logger.Info("Parsing Array List Started:")
+	start := p.curToken.Position
+	if p.logging() {
+		p.logger.Info("Parsing Array List Started:")
+	}
+
+	if err := p.unexpectedEOFError("array", start); err != nil {
+		return nil, err
+	}
 
 	if p.peekTokenIs(end) {
 		p.nextToken()
-		p.logger.Info("Empty Array:")
+		if p.logging() {
+			p.logger.Info("Empty Array:")
+		}
 		return list, nil
 	}
 
-	p.logger.Info("Parsing Array List:",
-		"endTokenType", end,
-		"endFound", false,
-		"list", list,
-	)
+	if p.logging() {
+		p.logger.Info("Parsing Array List:",
+			"endTokenType", end,
+			"endFound", false,
+			"list", list,
+		)
+	}
 
 	err := p.consumeAndParseValue(&list)
 	if err != nil {
@@ -279,13 +1202,23 @@ func (p *Parser) parseArrayList(end token.TokenType) ([]ast.Element, *JSONErr) {
 	}
 
 	for p.peekTokenIs(token.COMMA) {
-		p.logger.Info("Parsing Array List and peekTokenIs Comma")
+		if p.logging() {
+			p.logger.Info("Parsing Array List and peekTokenIs Comma")
+		}
 		p.nextToken()
+		p.attachCommaComments(list[len(list)-1])
+		p.setTrailingTrivia(list[len(list)-1])
+
+		if err := p.unexpectedEOFError("array", start); err != nil {
+			return nil, err
+		}
 
 		if p.peekTokenIs(end) {
-			msg := fmt.Sprintf("Expected 'STRING', 'NUMBER', 'TRUE', 'FALSE', 'NULL'. got '%v' instead\n",
-				p.peekToken.Type)
-			return nil, &JSONErr{Msg: msg, Pos: p.peekToken.Position}
+			return nil, p.trailingCommaError(p.peekToken)
+		}
+
+		if err := p.tooManyElements("array", len(list)); err != nil {
+			return nil, err
 		}
 
 		err := p.consumeAndParseValue(&list)
@@ -294,13 +1227,22 @@ func (p *Parser) parseArrayList(end token.TokenType) ([]ast.Element, *JSONErr) {
 		}
 	}
 
+	if err := p.unexpectedEOFError("array", start); err != nil {
+		return nil, err
+	}
+
 	if !p.peekTokenIs(end) && !p.curTokenIs(token.COMMA) {
+		if err := p.illegalTokenError(p.peekToken); err != nil {
+			return nil, err
+		}
 		msg := fmt.Sprintf("Expected ',', ']'. got '%v' instead\n", p.peekToken.Type)
 		return nil, &JSONErr{Msg: msg, Pos: p.peekToken.Position}
 	}
 	p.nextToken()
 
-	p.logger.Info("Parsing Array List Completed:", "list", list, "jsonError", p.JSONErr)
+	if p.logging() {
+		p.logger.Info("Parsing Array List Completed:", "list", list, "jsonError", p.JSONErr)
+	}
 	return list, nil
 }
 
@@ -311,34 +1253,133 @@ func (p *Parser) consumeAndParseValue(list *[]ast.Element) *JSONErr {
 		return err
 	}
 	*list = append(*list, val)
-	p.logger.Info("ConsumeAndParseValue:", "elem", val.String())
+	if p.logging() {
+		p.logger.Info("ConsumeAndParseValue:", "elem", val.String())
+	}
 	return nil
 }
 
 func (p *Parser) nextToken() {
 	p.prvToken = p.curToken
 	p.curToken = p.peekToken
-	p.peekToken = p.lexer.NextToken()
-	p.logger.Info("Fetching New Token:",
-		"prevToken", p.prvToken.Literal,
-		"prevTokenType", p.prvToken.Type,
-		"prevTokenPos", p.prvToken.Position,
-		"currentToken", p.curToken.Literal,
-		"currentTokenType", p.curToken.Type,
-		"currentTokenPos", p.curToken.Position,
-		"peekTokenPos", p.peekToken.Position,
-		"peekToken", p.peekToken.Literal,
-		"peekTokenType", p.peekToken.Type,
-	)
+	p.curComments = p.peekComments
+	p.peekComments = nil
+	p.curTrivia = p.peekTrivia
+	end := p.lexer.Offset()
+	p.peekToken = p.readToken()
+	p.peekOffset = p.lexer.TokenOffset()
+	for p.peekToken.Type == token.COMMENT {
+		p.peekComments = append(p.peekComments, p.peekToken)
+		p.peekToken = p.readToken()
+	}
+	if p.trivia {
+		p.peekTrivia = p.lexer.Slice(end, p.lexer.TokenOffset())
+	}
+	if p.logging() {
+		p.logger.Info("Fetching New Token:",
+			"prevToken", p.prvToken.Literal,
+			"prevTokenType", p.prvToken.Type,
+			"prevTokenPos", p.prvToken.Position,
+			"currentToken", p.curToken.Literal,
+			"currentTokenType", p.curToken.Type,
+			"currentTokenPos", p.curToken.Position,
+			"peekTokenPos", p.peekToken.Position,
+			"peekToken", p.peekToken.Literal,
+			"peekTokenType", p.peekToken.Type,
+		)
+	}
+}
+
+// readToken returns the next token of the lexer, recording it for
+// ParseWithTokens. The lexer keeps returning EOF at the end of the input,
+// only the first one is recorded. Without AllowUndefined, `undefined` is
+// an ILLEGAL token like any other unknown word.
+func (p *Parser) readToken() token.Token {
+	tok := p.lexer.NextToken()
+	if tok.Type == token.UNDEFINED && !p.allowUndefined {
+		tok.Type = token.ILLEGAL
+	}
+	if p.recordTokens {
+		if n := len(p.tokens); n == 0 || p.tokens[n-1].Type != token.EOF {
+			p.tokens = append(p.tokens, tok)
+		}
+	}
+	return tok
+}
+
+// attachCommaComments attaches the comments around the current comma to
+// the element before it. Comments after the comma only count when they sit
+// on the same line, anything further down belongs to the next element.
+func (p *Parser) attachCommaComments(e ast.Element) {
+	ast.AddTrailingComments(e, commentLiterals(p.curComments)...)
+
+	i := 0
+	for i < len(p.peekComments) && p.peekComments[i].Position.Line == p.curToken.Position.Line {
+		i++
+	}
+	ast.AddTrailingComments(e, commentLiterals(p.peekComments[:i])...)
+	p.peekComments = p.peekComments[i:]
+}
+
+// intern replaces the text of key with the shared copy for that text when
+// interning is enabled.
+func (p *Parser) intern(key ast.Element) {
+	sl, ok := key.(*ast.StringLiteral)
+	if !ok || p.interned == nil {
+		return
+	}
+
+	text, ok := p.interned[sl.Value]
+	if !ok {
+		text = strings.Clone(sl.Value)
+		p.interned[text] = text
+	}
+	sl.Value = text
+	sl.Token.Literal = text
+}
+
+// setTrivia records before as the text preceding e.
+func (p *Parser) setTrivia(e ast.Element, before string) {
+	if t := ast.TriviaOf(e); p.trivia && t != nil {
+		t.Before = before
+	}
+}
+
+// setTrailingTrivia records the text before the current token, which
+// follows e, as the text after e.
+func (p *Parser) setTrailingTrivia(e ast.Element) {
+	if t := ast.TriviaOf(e); p.trivia && t != nil {
+		t.After = p.curTrivia
+	}
+}
+
+// setClosingTrivia records the text before the closing bracket the parser
+// is on.
+func (p *Parser) setClosingTrivia(e ast.Element) {
+	if t := ast.TriviaOf(e); p.trivia && t != nil {
+		t.Closing = p.curTrivia
+	}
+}
+
+func commentLiterals(comments []token.Token) []string {
+	literals := make([]string, 0, len(comments))
+	for _, c := range comments {
+		literals = append(literals, c.Literal)
+	}
+	return literals
 }
 
 func (p *Parser) expectPeek(t token.TokenType) *JSONErr {
 	if ok := p.peekTokenIs(t); ok {
-		p.logger.Info("Checking nextTokenType:", string(t), ok)
+		if p.logging() {
+			p.logger.Info("Checking nextTokenType:", string(t), ok)
+		}
 		p.nextToken()
 		return nil
 	} else {
-		p.logger.Info("Checking nextTokenType:", string(t), ok)
+		if p.logging() {
+			p.logger.Info("Checking nextTokenType:", string(t), ok)
+		}
 		return p.peekError(t)
 	}
 }
@@ -350,6 +1391,40 @@ func (p *Parser) peekError(t token.TokenType) *JSONErr {
 	return &JSONErr{Msg: msg, Pos: p.peekToken.Position}
 }
 
+func (p *Parser) enterNested() *JSONErr {
+	p.depth++
+
+	if p.maxDepth > 0 && p.depth > p.maxDepth {
+		msg := fmt.Sprintf("Maximum nesting depth of %d exceeded\n", p.maxDepth)
+		return &JSONErr{Msg: msg, Pos: p.curToken.Position}
+	}
+	return nil
+}
+
+func (p *Parser) leaveNested() {
+	p.depth--
+}
+
+// tooManyElements reports an error when a construct already holding count
+// elements is about to receive another one past the maxElements limit.
+func (p *Parser) tooManyElements(construct string, count int) *JSONErr {
+	if p.maxElements <= 0 || count < p.maxElements {
+		return nil
+	}
+
+	msg := fmt.Sprintf("Maximum of %d elements per %s exceeded\n", p.maxElements, construct)
+	return &JSONErr{Msg: msg, Pos: p.peekToken.Position}
+}
+
+func (p *Parser) unexpectedEOFError(construct string, start token.Position) *JSONErr {
+	if !p.peekTokenIs(token.EOF) {
+		return nil
+	}
+
+	msg := fmt.Sprintf("Unexpected end of input while parsing %s\n", construct)
+	return &JSONErr{Msg: msg, Pos: start}
+}
+
 func (p *Parser) curTokenIs(t token.TokenType) bool {
 	return p.curToken.Type == t
 }
@@ -363,6 +1438,10 @@ func (p *Parser) registerElement(tokenType token.TokenType, fn parseFn) {
 }
 
 func (p *Parser) noParseFnError(t token.Token) *JSONErr {
+	if err := p.illegalTokenError(t); err != nil {
+		return err
+	}
+
 	msg := ""
 
 	switch p.prvToken.Type {
@@ -382,17 +1461,74 @@ func (p *Parser) noParseFnError(t token.Token) *JSONErr {
 	return &JSONErr{Msg: msg, Pos: p.curToken.Position}
 }
 
-func (p *Parser) isDuplicateProperty(propMap map[ast.Element]ast.Element, prop ast.Element) bool {
-	for p := range propMap {
-		if prop.String() == p.String() {
-			return true
+// nonStringKeyError reports a number, boolean or null used as an object
+// key, e.g. {1: "x"}, naming the type that was found.
+func (p *Parser) nonStringKeyError(t token.Token) *JSONErr {
+	var got string
+	switch t.Type {
+	case token.NUMBER:
+		got = "number"
+	case token.TRUE, token.FALSE:
+		got = "boolean"
+	case token.NULL:
+		got = "null"
+	default:
+		return nil
+	}
+
+	msg := fmt.Sprintf("Object keys must be strings, got %s\n", got)
+	return &JSONErr{Msg: msg, Pos: t.Position}
+}
+
+// unquotedKeyError reports a bare word used as an object key, e.g.
+// {name: "x"}, as JavaScript allows. Words the lexer has a hint for, e.g.
+// {None: 1}, are reported with that hint.
+func (p *Parser) unquotedKeyError(t token.Token) *JSONErr {
+	if err := p.illegalTokenError(t); err != nil {
+		return err
+	}
+	if (t.Type != token.ILLEGAL && t.Type != token.UNDEFINED) || !isIdentifier(t.Literal) {
+		return nil
+	}
+
+	return &JSONErr{Msg: "Unquoted object key; keys must be double-quoted strings in JSON\n", Pos: t.Position}
+}
+
+// trailingCommaError reports the closing bracket or brace t found right
+// after a comma.
+func (p *Parser) trailingCommaError(t token.Token) *JSONErr {
+	msg := fmt.Sprintf("Trailing commas are not allowed in JSON; remove the ',' before '%s'\n", t.Literal)
+	return &JSONErr{Msg: msg, Pos: t.Position}
+}
+
+// isIdentifier reports whether s is a bare word as read by the lexer.
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.IsLetter(r) && r != '_' {
+			return false
 		}
 	}
-	return false
+	return true
+}
+
+// illegalTokenError reports an ILLEGAL token using the reason the lexer
+// gave for rejecting it. It returns nil when there is no reason to report.
+func (p *Parser) illegalTokenError(t token.Token) *JSONErr {
+	if t.Type != token.ILLEGAL || t.Reason == "" {
+		return nil
+	}
+
+	msg := fmt.Sprintf("%s\n", t.Reason)
+	return &JSONErr{Msg: msg, Pos: t.Position}
 }
 
 func (p *Parser) checkNumberFormat(n ast.Element) (ast.Element, *JSONErr) {
-	p.logger.Info("Checking Number Format:", "number", n.String())
+	if p.logging() {
+		p.logger.Info("Checking Number Format:", "number", n.String())
+	}
 	var pattern = regexp.MustCompile(`^[-+]?(([1-9][0-9]*)|0)(\.[0-9]+)?([eE][-+]?[0-9]+)?$`)
 	matched := pattern.MatchString(n.String())
 	if !matched {
@@ -406,21 +1542,72 @@ func (p *Parser) checkNumberFormat(n ast.Element) (ast.Element, *JSONErr) {
 func (p *Parser) checkEscapedSequence(str string) (int, *JSONErr) {
 	switch str[1] {
 	case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
-		p.logger.Info("Checking Escapped Sequence in String:", "sequence", string(str[1]))
+		if p.logging() {
+			p.logger.Info("Checking Escapped Sequence in String:", "sequence", string(str[1]))
+		}
 		return 2, nil
 	case 'u':
-		p.logger.Info("Checking Unicode Escapped Sequence in String:")
-		if len(str) >= 6 && p.isValidHexSequence(str[2:6]) {
+		if p.logging() {
+			p.logger.Info("Checking Unicode Escapped Sequence in String:")
+		}
+		if p.braceUnicode && len(str) > 2 && str[2] == '{' {
+			if n := p.braceUnicodeLen(str); n > 0 {
+				return n, nil
+			}
+		} else if len(str) >= 6 && p.isValidHexSequence(str[2:6]) {
 			return 6, nil
 		}
 		msg := "Invalid unicode escape sequence\n"
-		p.logger.Info("Failed Checking Escapped Sequence:", "error", p.JSONErr)
+		if p.logging() {
+			p.logger.Info("Failed Checking Escapped Sequence:", "error", p.JSONErr)
+		}
 		return 0, &JSONErr{Msg: msg, Pos: p.curToken.Position}
-	default:
-		msg := fmt.Sprintf("Invalid escape sequence\n")
-		p.logger.Info("Failed Checking Escapped Sequence:", "error", p.JSONErr)
+	case 'x':
+		if !p.extendedEscapes {
+			break
+		}
+		if p.logging() {
+			p.logger.Info("Checking Hex Escapped Sequence in String:")
+		}
+		if len(str) >= 4 && p.isHexDigit(rune(str[2])) && p.isHexDigit(rune(str[3])) {
+			return 4, nil
+		}
+		msg := "Invalid hex escape sequence\n"
+		if p.logging() {
+			p.logger.Info("Failed Checking Escapped Sequence:", "error", p.JSONErr)
+		}
 		return 0, &JSONErr{Msg: msg, Pos: p.curToken.Position}
+	case '0':
+		if !p.extendedEscapes {
+			break
+		}
+		if p.logging() {
+			p.logger.Info("Checking Escapped Sequence in String:", "sequence", string(str[1]))
+		}
+		return 2, nil
+	}
+
+	msg := fmt.Sprintf("Invalid escape sequence\n")
+	if p.logging() {
+		p.logger.Info("Failed Checking Escapped Sequence:", "error", p.JSONErr)
+	}
+	return 0, &JSONErr{Msg: msg, Pos: p.curToken.Position}
+}
+
+// braceUnicodeLen returns the length of the \u{X...} escape at the start
+// of str, or 0 when it is not one of 1 to 6 hex digits naming a valid rune.
+// Surrogates are not valid runes on their own, so \u{D800} is rejected.
+func (p *Parser) braceUnicodeLen(str string) int {
+	end := strings.IndexByte(str, '}')
+	if end < 4 || end > 9 {
+		return 0
+	}
+
+	n, err := strconv.ParseUint(str[3:end], 16, 32)
+	if err != nil || !utf8.ValidRune(rune(n)) {
+		return 0
 	}
+	return end + 1
 }
 
 func (p *Parser) isValidHexSequence(seq string) bool {
@@ -3,7 +3,13 @@ package parser
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"math/big"
+	"strings"
 	"testing"
+	"unsafe"
 
 	"github.com/nobletk/json-parser/internal/ast"
 	"github.com/nobletk/json-parser/internal/lexer"
@@ -50,6 +56,437 @@ func TestOutToJSON(t *testing.T) {
 	assert.Equal(t, expected, string(jsonData))
 }
 
+func TestParseStringAndBytes(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		opts        []Option
+		expected    interface{}
+		expectedErr *JSONErr
+	}{
+		{
+			name:  "Object With Nested Values",
+			input: `{"key1": "value", "key2": -123, "key3": ["value", 1, true, null, -0.2e2]}`,
+			expected: map[string]interface{}{
+				"key1": "value",
+				"key2": float64(-123),
+				"key3": []interface{}{"value", float64(1), true, nil, float64(-20)},
+			},
+		},
+		{
+			name:     "Options Are Applied",
+			input:    `{"a": 1, "a": 2}`,
+			opts:     []Option{WithDuplicateKeys(DuplicateKeysLast)},
+			expected: map[string]interface{}{"a": float64(2)},
+		},
+		{
+			name:  "Missing Value",
+			input: `{"key": }`,
+			expectedErr: &JSONErr{
+				Msg: "Missing value for key 'key'\n",
+				Pos: token.Position{
+					Column: 9,
+					Line:   1,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		parsers := map[string]func() (*ast.JSONFile, *JSONErr){
+			"String": func() (*ast.JSONFile, *JSONErr) { return ParseString(tt.input, tt.opts...) },
+			"Bytes":  func() (*ast.JSONFile, *JSONErr) { return ParseBytes([]byte(tt.input), tt.opts...) },
+		}
+		for name, parse := range parsers {
+			t.Run(tt.name+" "+name, func(t *testing.T) {
+				jf, jErr := parse()
+
+				if tt.expectedErr != nil {
+					assert.Empty(t, jf, "jsonFile should be empty")
+					assert.Equal(t, tt.expectedErr, jErr)
+					return
+				}
+				require.Empty(t, jErr, "jsonErr should be empty")
+				assert.Equal(t, tt.expected, jf.Elements[0].ToInterface())
+			})
+		}
+	}
+}
+
+func TestParseToInterface(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		opts        []Option
+		expected    interface{}
+		expectedErr *JSONErr
+	}{
+		{
+			name:     "Object",
+			input:    `{"a": [1, "b"], "c": null}`,
+			expected: map[string]interface{}{"a": []interface{}{float64(1), "b"}, "c": nil},
+		},
+		{
+			name:     "Array",
+			input:    `[true, {"n": -1.5}]`,
+			expected: []interface{}{true, map[string]interface{}{"n": -1.5}},
+		},
+		{
+			name:     "Scalar Root",
+			input:    ` "text" `,
+			opts:     []Option{AllowScalarRoot()},
+			expected: "text",
+		},
+		{
+			name:     "Number Root",
+			input:    `42`,
+			opts:     []Option{AllowScalarRoot()},
+			expected: float64(42),
+		},
+		{
+			name:  "Scalar Root Not Allowed",
+			input: `42`,
+			expectedErr: &JSONErr{
+				Msg: "Expected '{' or '[', got 'NUMBER' instead\n",
+				Pos: token.Position{
+					Column: 1,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:  "Invalid Scalar Root",
+			input: `--1`,
+			opts:  []Option{AllowScalarRoot()},
+			expectedErr: &JSONErr{
+				Msg: "Invalid number '--1': unexpected '-'\n",
+				Pos: token.Position{
+					Column: 1,
+					Line:   1,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, jErr := ParseToInterface(tt.input, tt.opts...)
+
+			assert.Equal(t, tt.expectedErr, jErr)
+			assert.Equal(t, tt.expected, v)
+		})
+	}
+}
+
+func TestWithAllowedRoots(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		roots       []token.TokenType
+		expected    interface{}
+		expectedErr *JSONErr
+	}{
+		{
+			name:     "Object Only With Object",
+			input:    `{"a": 1}`,
+			roots:    []token.TokenType{token.LBRACE},
+			expected: map[string]interface{}{"a": float64(1)},
+		},
+		{
+			name:  "Object Only With Array",
+			input: "\n  [1]",
+			roots: []token.TokenType{token.LBRACE},
+			expectedErr: &JSONErr{
+				Msg: "This input requires a JSON object at the root, got '[' instead\n",
+				Pos: token.Position{
+					Column: 3,
+					Line:   2,
+				},
+			},
+		},
+		{
+			name:     "Array Only With Array",
+			input:    `[1]`,
+			roots:    []token.TokenType{token.LBRACKET},
+			expected: []interface{}{float64(1)},
+		},
+		{
+			name:  "Array Only With Object",
+			input: `{"a": 1}`,
+			roots: []token.TokenType{token.LBRACKET},
+			expectedErr: &JSONErr{
+				Msg: "This input requires a JSON array at the root, got '{' instead\n",
+				Pos: token.Position{
+					Column: 1,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:     "Scalar Types",
+			input:    `false`,
+			roots:    []token.TokenType{token.LBRACE, token.TRUE, token.FALSE},
+			expected: false,
+		},
+		{
+			name:     "True Accepts False",
+			input:    `false`,
+			roots:    []token.TokenType{token.TRUE},
+			expected: false,
+		},
+		{
+			name:     "False Accepts True",
+			input:    `true`,
+			roots:    []token.TokenType{token.FALSE},
+			expected: true,
+		},
+		{
+			name:  "Scalar Types Not Matched",
+			input: `"text"`,
+			roots: []token.TokenType{token.LBRACE, token.TRUE, token.FALSE},
+			expectedErr: &JSONErr{
+				Msg: "This input requires a JSON object or boolean at the root, got 'STRING' instead\n",
+				Pos: token.Position{
+					Column: 1,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:  "Illegal Token",
+			input: `--1`,
+			roots: []token.TokenType{token.LBRACE},
+			expectedErr: &JSONErr{
+				Msg: "Invalid number '--1': unexpected '-'\n",
+				Pos: token.Position{
+					Column: 1,
+					Line:   1,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, jErr := ParseToInterface(tt.input, WithAllowedRoots(tt.roots...))
+
+			assert.Equal(t, tt.expectedErr, jErr)
+			assert.Equal(t, tt.expected, v)
+		})
+	}
+}
+
+func TestParseObjectAndArray(t *testing.T) {
+	obj, jErr := ParseObject(`{"a": [1]}`)
+	require.Empty(t, jErr, "jsonErr should be empty")
+	assert.Equal(t, `{"a":[1]}`, obj.String())
+
+	arr, jErr := ParseArray(" [1, {}]")
+	require.Empty(t, jErr, "jsonErr should be empty")
+	assert.Len(t, arr.Elements, 2)
+
+	tests := []struct {
+		name        string
+		parse       func() *JSONErr
+		expectedErr *JSONErr
+	}{
+		{
+			name: "Object Expected",
+			parse: func() *JSONErr {
+				_, jErr := ParseObject("\n  [1]")
+				return jErr
+			},
+			expectedErr: &JSONErr{
+				Msg: "Expected object at root, got array\n",
+				Pos: token.Position{
+					Column: 3,
+					Line:   2,
+				},
+			},
+		},
+		{
+			name: "Array Expected",
+			parse: func() *JSONErr {
+				_, jErr := ParseArray(`{"a": 1}`)
+				return jErr
+			},
+			expectedErr: &JSONErr{
+				Msg: "Expected array at root, got object\n",
+				Pos: token.Position{
+					Column: 1,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name: "Scalar Root Allowed",
+			parse: func() *JSONErr {
+				_, jErr := ParseObject(" true", AllowScalarRoot())
+				return jErr
+			},
+			expectedErr: &JSONErr{
+				Msg: "Expected object at root, got boolean\n",
+				Pos: token.Position{
+					Column: 2,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name: "Empty Input Allowed",
+			parse: func() *JSONErr {
+				_, jErr := ParseObject("  ", AllowEmptyInput())
+				return jErr
+			},
+			expectedErr: &JSONErr{
+				Msg: "Expected object at root, got empty input\n",
+				Pos: token.Position{
+					Column: 1,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name: "Invalid JSON",
+			parse: func() *JSONErr {
+				_, jErr := ParseArray(`[1,]`)
+				return jErr
+			},
+			expectedErr: &JSONErr{
+				Msg: "Trailing commas are not allowed in JSON; remove the ',' before ']'\n",
+				Pos: token.Position{
+					Column: 4,
+					Line:   1,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedErr, tt.parse())
+		})
+	}
+}
+
+func TestCommentsRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		indent string
+	}{
+		{
+			name: "Commented Object",
+			input: `// leading comment
+{
+  // key comment
+  "key1": "value", // trailing comment
+  "key2": /* value comment */ -123,
+  "key3": [
+    1, // first
+    /* second */
+    true
+  ],
+  "key4": {
+    "key5": null /* last */
+  }
+} // end`,
+			indent: "  ",
+		},
+		{
+			name:   "Commented Compact Array",
+			input:  "[/* a */ 1, // b\n{\"key\" /* c */:2}]",
+			indent: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := mylog.CreateLogger(true)
+			l := lexer.New(log, tt.input, lexer.WithComments())
+			p := New(l)
+			jf, jsonErr := p.ParseFile()
+			require.Empty(t, jsonErr, "jsonErr should be empty")
+			require.Len(t, jf.Elements, 1, "length of elements isn't correct")
+
+			out, err := ast.MarshalIndent(jf.Elements[0], tt.indent)
+			require.NoError(t, err)
+			assert.Equal(t, tt.input, string(out))
+		})
+	}
+}
+
+func TestTriviaRoundTrip(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		lexerOpts []lexer.Option
+	}{
+		{
+			name:  "Tab Indented Object",
+			input: "{\n\t\"key1\": \"value\",\n\t\"key2\": [1, 2,\t3],\n\t\"key3\": {}\n}\n",
+		},
+		{
+			name:  "Odd Spacing",
+			input: "  \r\n[ 1 ,2 ,  {\"a\"  :  true ,\"b\":null}  ,[ ] , {  } ]\r\n\r\n",
+		},
+		{
+			name:  "Compact With Escapes",
+			input: `{"a\/b":"\u00e9\n","c":[-1.50E+3,0,"x"]}`,
+		},
+		{
+			name: "Comments",
+			input: `// leading comment
+{
+    // key comment
+    "key1"  :  "value", // trailing comment
+    "key2": /* value comment */ -123,
+    "key3": [
+        1, // first
+        /* second */
+        true
+    ]
+} // end
+`,
+			lexerOpts: []lexer.Option{lexer.WithComments()},
+		},
+		{
+			name:      "Extended Numbers",
+			input:     "[ 0x1F,\t+1.5 ]",
+			lexerOpts: []lexer.Option{lexer.AllowHexNumbers(), lexer.AllowLeadingPlus()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := mylog.CreateLogger(true)
+			l := lexer.New(log, tt.input, tt.lexerOpts...)
+			p := New(l, WithTrivia())
+			jf, jsonErr := p.ParseFile()
+			require.Empty(t, jsonErr, "jsonErr should be empty")
+			require.Len(t, jf.Elements, 1, "length of elements isn't correct")
+
+			m := &ast.Marshaler{PreserveTrivia: true}
+			out, err := m.Marshal(jf.Elements[0])
+			require.NoError(t, err)
+			assert.Equal(t, tt.input, string(out))
+		})
+	}
+}
+
+func TestTriviaEdit(t *testing.T) {
+	jf, jsonErr := ParseString("{\n\t\"a\": 1,\n\t\"b\": [true]\n}", WithTrivia())
+	require.Empty(t, jsonErr, "jsonErr should be empty")
+
+	obj := jf.Elements[0].(*ast.Object)
+	obj.Pairs[obj.Keys[0]] = &ast.NumberLiteral{Token: token.Token{Literal: "2"}, Value: 2}
+
+	m := &ast.Marshaler{PreserveTrivia: true}
+	out, err := m.Marshal(obj)
+	require.NoError(t, err)
+	assert.Equal(t, "{\n\t\"a\":2,\n\t\"b\": [true]\n}", string(out))
+}
+
 func TestValidJSONObject(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -211,7 +648,7 @@ func TestInvalidJSON(t *testing.T) {
 			name:  "Empty Input",
 			input: ``,
 			expectedErr: &JSONErr{
-				Msg: "Expected '{' or '[', got 'EOF' instead\n",
+				Msg: "Unexpected end of input, expected a JSON value\n",
 				Pos: token.Position{
 					Column: 1,
 					Line:   1,
@@ -244,51 +681,51 @@ func TestInvalidJSON(t *testing.T) {
 			name:  "Unclosed Object",
 			input: `{`,
 			expectedErr: &JSONErr{
-				Msg: "Expected 'STRING', '}', got 'EOF' instead\n",
+				Msg: "Unexpected end of input while parsing object\n",
 				Pos: token.Position{
-					Column: 2,
+					Column: 1,
 					Line:   1,
 				},
 			},
 		},
 		{
-			name:  "Trailing Comma After Left Brace",
-			input: `{,`,
+			name:  "Unterminated Object After Value",
+			input: `{"key": "value"`,
 			expectedErr: &JSONErr{
-				Msg: "Expected 'STRING', '}', got ',' instead\n",
+				Msg: "Unexpected end of input while parsing object\n",
 				Pos: token.Position{
-					Column: 2,
+					Column: 1,
 					Line:   1,
 				},
 			},
 		},
 		{
-			name:  "Trailing Comma After Property",
-			input: `{"key":,`,
+			name:  "Unterminated Object After Comma",
+			input: "{\n  \"key1\": 1,",
 			expectedErr: &JSONErr{
-				Msg: "Expected 'STRING', 'NUMBER', 'NULL', 'TRUE', 'FALSE', '{', '[', got ',' instead\n",
+				Msg: "Unexpected end of input while parsing object\n",
 				Pos: token.Position{
+					Column: 1,
 					Line:   1,
-					Column: 8,
 				},
 			},
 		},
 		{
-			name:  "Unclosed Array",
-			input: `[`,
+			name:  "Unterminated Nested Object",
+			input: "[1,\n  {\"key\":",
 			expectedErr: &JSONErr{
-				Msg: "Expected 'STRING', 'NUMBER', 'NULL', 'TRUE', 'FALSE', '{', '[', ']', got 'EOF' instead\n",
+				Msg: "Unexpected end of input while parsing object\n",
 				Pos: token.Position{
-					Column: 2,
-					Line:   1,
+					Column: 3,
+					Line:   2,
 				},
 			},
 		},
 		{
-			name:  "Wrong Closing For An Array",
-			input: `[}`,
+			name:  "Trailing Comma After Left Brace",
+			input: `{,`,
 			expectedErr: &JSONErr{
-				Msg: "Expected 'STRING', 'NUMBER', 'NULL', 'TRUE', 'FALSE', '{', '[', ']', got '}' instead\n",
+				Msg: "Expected 'STRING', '}', got ',' instead\n",
 				Pos: token.Position{
 					Column: 2,
 					Line:   1,
@@ -296,13 +733,178 @@ func TestInvalidJSON(t *testing.T) {
 			},
 		},
 		{
-			name:  "Trailing Comma In An Array",
-			input: `[,`,
+			name:  "Newline In String",
+			input: "{\"a\": \"x\ny\"}",
 			expectedErr: &JSONErr{
-				Msg: "Expected 'STRING', 'NUMBER', 'NULL', 'TRUE', 'FALSE', '{', '[', ']', got ',' instead\n",
+				Msg: "Strings may not contain raw line breaks; use \\n or \\r\n",
 				Pos: token.Position{
-					Column: 2,
 					Line:   1,
+					Column: 9,
+				},
+			},
+		},
+		{
+			name:  "Carriage Return In String",
+			input: "[\n  \"x\ry\"]",
+			expectedErr: &JSONErr{
+				Msg: "Strings may not contain raw line breaks; use \\n or \\r\n",
+				Pos: token.Position{
+					Line:   2,
+					Column: 5,
+				},
+			},
+		},
+		{
+			name:  "Missing Value Before Brace",
+			input: `{"a":}`,
+			expectedErr: &JSONErr{
+				Msg: "Missing value for key 'a'\n",
+				Pos: token.Position{
+					Line:   1,
+					Column: 6,
+				},
+			},
+		},
+		{
+			name:  "Missing Value Before Comma",
+			input: `{"a":,}`,
+			expectedErr: &JSONErr{
+				Msg: "Missing value for key 'a'\n",
+				Pos: token.Position{
+					Line:   1,
+					Column: 6,
+				},
+			},
+		},
+		{
+			name:  "Colon Followed By Bracket",
+			input: `{"a":]}`,
+			expectedErr: &JSONErr{
+				Msg: "Expected 'STRING', 'NUMBER', 'NULL', 'TRUE', 'FALSE', '{', '[', got ']' instead\n",
+				Pos: token.Position{
+					Line:   1,
+					Column: 6,
+				},
+			},
+		},
+		{
+			name:  "Trailing Comma After Property",
+			input: `{"key":,`,
+			expectedErr: &JSONErr{
+				Msg: "Missing value for key 'key'\n",
+				Pos: token.Position{
+					Line:   1,
+					Column: 8,
+				},
+			},
+		},
+		{
+			name:  "Unclosed Array",
+			input: `[`,
+			expectedErr: &JSONErr{
+				Msg: "Unexpected end of input while parsing array\n",
+				Pos: token.Position{
+					Column: 1,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:  "Unterminated Array After Comma",
+			input: `[1, 2,`,
+			expectedErr: &JSONErr{
+				Msg: "Unexpected end of input while parsing array\n",
+				Pos: token.Position{
+					Column: 1,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:  "Unterminated Nested Array",
+			input: "{\"key\":\n  [true, null",
+			expectedErr: &JSONErr{
+				Msg: "Unexpected end of input while parsing array\n",
+				Pos: token.Position{
+					Column: 3,
+					Line:   2,
+				},
+			},
+		},
+		{
+			name:  "Wrong Closing For An Array",
+			input: `[}`,
+			expectedErr: &JSONErr{
+				Msg: "Expected 'STRING', 'NUMBER', 'NULL', 'TRUE', 'FALSE', '{', '[', ']', got '}' instead\n",
+				Pos: token.Position{
+					Column: 2,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:  "Trailing Comma In An Array",
+			input: `[,`,
+			expectedErr: &JSONErr{
+				Msg: "Expected 'STRING', 'NUMBER', 'NULL', 'TRUE', 'FALSE', '{', '[', ']', got ',' instead\n",
+				Pos: token.Position{
+					Column: 2,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:  "Missing Colon Between Key And Value",
+			input: `{"a" 1}`,
+			expectedErr: &JSONErr{
+				Msg: "Expected ':' after object key 'a', got 'NUMBER' instead\n",
+				Pos: token.Position{
+					Column: 6,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:  "Missing Comma Between Pairs",
+			input: `{"a":1 "b":2}`,
+			expectedErr: &JSONErr{
+				Msg: "Expected ',' or '}' after value, got 'STRING' instead\n",
+				Pos: token.Position{
+					Column: 8,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:  "Missing Comma Between Pairs On New Line",
+			input: "{\"a\": [1]\n  \"b\": 2}",
+			expectedErr: &JSONErr{
+				Msg: "Expected ',' or '}' after value, got 'STRING' instead\n",
+				Pos: token.Position{
+					Column: 3,
+					Line:   2,
+				},
+			},
+		},
+		{
+			name:  "Wrong Closing Bracket",
+			input: `{"a":1]`,
+			expectedErr: &JSONErr{
+				Msg: "Expected ',' or '}' after value, got ']' instead\n",
+				Pos: token.Position{
+					Column: 7,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:  "Missing Colon Before Nested Object",
+			input: "{\n  \"key\"\n  {}\n}",
+			expectedErr: &JSONErr{
+				Msg: "Expected ':' after object key 'key', got '{' instead\n",
+				Pos: token.Position{
+					Column: 3,
+					Line:   3,
 				},
 			},
 		},
@@ -321,7 +923,7 @@ func TestInvalidJSON(t *testing.T) {
 			name:  "Minus Not Followed By A Number",
 			input: `{"key1": - }`,
 			expectedErr: &JSONErr{
-				Msg: "Expected 'STRING', 'NUMBER', 'NULL', 'TRUE', 'FALSE', '{', '[', got 'ILLEGAL' instead\n",
+				Msg: "Invalid number '-': expected digit after '-'\n",
 				Pos: token.Position{
 					Column: 10,
 					Line:   1,
@@ -332,7 +934,18 @@ func TestInvalidJSON(t *testing.T) {
 			name:  "Minus Followed By Space",
 			input: `{"key1": - 1}`,
 			expectedErr: &JSONErr{
-				Msg: "Expected 'STRING', 'NUMBER', 'NULL', 'TRUE', 'FALSE', '{', '[', got 'ILLEGAL' instead\n",
+				Msg: "Invalid number '-': expected digit after '-'\n",
+				Pos: token.Position{
+					Column: 10,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:  "Double Minus",
+			input: `{"key1": --5}`,
+			expectedErr: &JSONErr{
+				Msg: "Invalid number '--5': unexpected '-'\n",
 				Pos: token.Position{
 					Column: 10,
 					Line:   1,
@@ -343,7 +956,7 @@ func TestInvalidJSON(t *testing.T) {
 			name:  "Decimal With No Leading Digit",
 			input: `{"key1": -.95}`,
 			expectedErr: &JSONErr{
-				Msg: "Expected 'STRING', 'NUMBER', 'NULL', 'TRUE', 'FALSE', '{', '[', got 'ILLEGAL' instead\n",
+				Msg: "Invalid number '-.95': expected digit after '-', got '.'\n",
 				Pos: token.Position{
 					Column: 10,
 					Line:   1,
@@ -354,7 +967,7 @@ func TestInvalidJSON(t *testing.T) {
 			name:  "Invalid Exponent",
 			input: `{"key1": -100e}`,
 			expectedErr: &JSONErr{
-				Msg: "Expected 'STRING', 'NUMBER', 'NULL', 'TRUE', 'FALSE', '{', '[', got 'ILLEGAL' instead\n",
+				Msg: "Invalid number '-100e': expected digit in exponent\n",
 				Pos: token.Position{
 					Column: 10,
 					Line:   1,
@@ -365,18 +978,62 @@ func TestInvalidJSON(t *testing.T) {
 			name:  "Not A Property Quotations",
 			input: `{key1: 0}`,
 			expectedErr: &JSONErr{
-				Msg: "Expected 'STRING', '}', got 'ILLEGAL' instead\n",
+				Msg: "Unquoted object key; keys must be double-quoted strings in JSON\n",
+				Pos: token.Position{
+					Column: 2,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:  "Numeric Key",
+			input: `{1: "x"}`,
+			expectedErr: &JSONErr{
+				Msg: "Object keys must be strings, got number\n",
+				Pos: token.Position{
+					Column: 2,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:  "Boolean Key",
+			input: `{true: 1}`,
+			expectedErr: &JSONErr{
+				Msg: "Object keys must be strings, got boolean\n",
+				Pos: token.Position{
+					Column: 2,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:  "Null Key",
+			input: `{null: 1}`,
+			expectedErr: &JSONErr{
+				Msg: "Object keys must be strings, got null\n",
 				Pos: token.Position{
 					Column: 2,
 					Line:   1,
 				},
 			},
 		},
+		{
+			name:  "Numeric Key After Comma",
+			input: `{"a": 1, -2.5: "x"}`,
+			expectedErr: &JSONErr{
+				Msg: "Object keys must be strings, got number\n",
+				Pos: token.Position{
+					Column: 10,
+					Line:   1,
+				},
+			},
+		},
 		{
 			name:  "Trailing Comma In An Object",
 			input: `{"key1": "value1", }`,
 			expectedErr: &JSONErr{
-				Msg: "Expected 'STRING', got '}' instead\n",
+				Msg: "Trailing commas are not allowed in JSON; remove the ',' before '}'\n",
 				Pos: token.Position{
 					Column: 20,
 					Line:   1,
@@ -387,7 +1044,7 @@ func TestInvalidJSON(t *testing.T) {
 			name:  "Trailing Comma In An Array",
 			input: `["value1", ]`,
 			expectedErr: &JSONErr{
-				Msg: "Expected 'STRING', 'NUMBER', 'TRUE', 'FALSE', 'NULL'. got ']' instead\n",
+				Msg: "Trailing commas are not allowed in JSON; remove the ',' before ']'\n",
 				Pos: token.Position{
 					Column: 12,
 					Line:   1,
@@ -398,9 +1055,9 @@ func TestInvalidJSON(t *testing.T) {
 			name:  "Comma After A Right Bracket Of An Array Instead of EOF",
 			input: `["value1"],`,
 			expectedErr: &JSONErr{
-				Msg: "Expected 'EOF', got ',' instead\n",
+				Msg: "Unexpected trailing content after JSON value\n",
 				Pos: token.Position{
-					Column: 12,
+					Column: 11,
 					Line:   1,
 				},
 			},
@@ -409,7 +1066,7 @@ func TestInvalidJSON(t *testing.T) {
 			name:  "No String After Comma In A JSON Object",
 			input: `{"key": ["value1"], }`,
 			expectedErr: &JSONErr{
-				Msg: "Expected 'STRING', got '}' instead\n",
+				Msg: "Trailing commas are not allowed in JSON; remove the ',' before '}'\n",
 				Pos: token.Position{
 					Column: 21,
 					Line:   1,
@@ -420,9 +1077,9 @@ func TestInvalidJSON(t *testing.T) {
 			name:  "String After Right Curly Brace Instead Of EOF",
 			input: `{"key": "value1"} "misplaced quoted value"`,
 			expectedErr: &JSONErr{
-				Msg: "Expected 'EOF', got 'STRING' instead\n",
+				Msg: "Unexpected trailing content after JSON value\n",
 				Pos: token.Position{
-					Column: 43,
+					Column: 19,
 					Line:   1,
 				},
 			},
@@ -442,7 +1099,7 @@ func TestInvalidJSON(t *testing.T) {
 			name:  "Illegal Token String With Closing Quotation",
 			input: "[\"value]",
 			expectedErr: &JSONErr{
-				Msg: "Expected 'STRING', 'NUMBER', 'NULL', 'TRUE', 'FALSE', '{', '[', ']', got 'ILLEGAL' instead\n",
+				Msg: "Unterminated string\n",
 				Pos: token.Position{
 					Column: 2,
 					Line:   1,
@@ -475,7 +1132,117 @@ func TestInvalidJSON(t *testing.T) {
 			name:  "Single Quotations Wrapping String",
 			input: "{\"key\": ['value']}",
 			expectedErr: &JSONErr{
-				Msg: "Expected 'STRING', 'NUMBER', 'NULL', 'TRUE', 'FALSE', '{', '[', ']', got 'ILLEGAL' instead\n",
+				Msg: "Strings must use double quotes in JSON; use \"value\" instead of 'value'\n",
+				Pos: token.Position{
+					Column: 10,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:  "Python None As Key",
+			input: "{None: 1}",
+			expectedErr: &JSONErr{
+				Msg: "Python-style 'None' found; use 'null'\n",
+				Pos: token.Position{
+					Column: 2,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:  "Python True As Key After Comma",
+			input: "{\"a\": 1, True: 2}",
+			expectedErr: &JSONErr{
+				Msg: "Python-style 'True' found; use 'true'\n",
+				Pos: token.Position{
+					Column: 10,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:  "Single Quoted Key",
+			input: "{'x': 1}",
+			expectedErr: &JSONErr{
+				Msg: "Strings must use double quotes in JSON; use \"x\" instead of 'x'\n",
+				Pos: token.Position{
+					Column: 2,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:  "Python True",
+			input: "{\"a\": True}",
+			expectedErr: &JSONErr{
+				Msg: "Python-style 'True' found; use 'true'\n",
+				Pos: token.Position{
+					Column: 7,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:  "Python None In Array",
+			input: "[1, None]",
+			expectedErr: &JSONErr{
+				Msg: "Python-style 'None' found; use 'null'\n",
+				Pos: token.Position{
+					Column: 5,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:  "Uppercase False",
+			input: "[FALSE]",
+			expectedErr: &JSONErr{
+				Msg: "Keywords are lowercase in JSON; use 'false' instead of 'FALSE'\n",
+				Pos: token.Position{
+					Column: 2,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:  "Line Comment",
+			input: "{\"a\": 1 // comment\n}",
+			expectedErr: &JSONErr{
+				Msg: "Comments are not allowed in JSON; did you mean to enable comments?\n",
+				Pos: token.Position{
+					Column: 9,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:  "Comment Before Root",
+			input: "// comment\n[]",
+			expectedErr: &JSONErr{
+				Msg: "Comments are not allowed in JSON; did you mean to enable comments?\n",
+				Pos: token.Position{
+					Column: 1,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:  "Block Comment In Array",
+			input: "[1 /* one */, 2]",
+			expectedErr: &JSONErr{
+				Msg: "Comments are not allowed in JSON; did you mean to enable comments?\n",
+				Pos: token.Position{
+					Column: 4,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:  "Unquoted Key After Comma",
+			input: "{\"a\": 1, b: 2}",
+			expectedErr: &JSONErr{
+				Msg: "Unquoted object key; keys must be double-quoted strings in JSON\n",
 				Pos: token.Position{
 					Column: 10,
 					Line:   1,
@@ -497,29 +1264,155 @@ func TestInvalidJSON(t *testing.T) {
 	}
 }
 
-func TestParseArray(t *testing.T) {
+func TestTrailingContent(t *testing.T) {
 	tests := []struct {
-		name     string
-		input    string
-		expected []interface{}
+		name        string
+		input       string
+		expectedPos token.Position
 	}{
 		{
-			name:     "Empty Array",
-			input:    `[]`,
-			expected: []interface{}{},
+			name:        "Trailing String",
+			input:       `{"a":1} "garbage"`,
+			expectedPos: token.Position{Column: 9, Line: 1},
 		},
 		{
-			name:     "Array Of Integers",
-			input:    `[1, 2, 3]`,
-			expected: []interface{}{1, 2, 3},
+			name:        "Trailing Number",
+			input:       `[1, 2]  3`,
+			expectedPos: token.Position{Column: 9, Line: 1},
 		},
 		{
-			name:     "Array Of Strings",
-			input:    `["x", "y", "z"]`,
-			expected: []interface{}{"x", "y", "z"},
+			name:        "Trailing Brace",
+			input:       "{\"a\":1}\n}",
+			expectedPos: token.Position{Column: 1, Line: 2},
 		},
 		{
-			name:     "Array Of Booleans And Null",
+			name:        "Second Object",
+			input:       `{"a":1}{"b":2}`,
+			expectedPos: token.Position{Column: 8, Line: 1},
+		},
+		{
+			name:        "Trailing Bare Word",
+			input:       `{"a":1} garbage`,
+			expectedPos: token.Position{Column: 9, Line: 1},
+		},
+		{
+			name:        "Value After Blank Lines",
+			input:       "[1]\r\n\n\t2\n",
+			expectedPos: token.Position{Column: 2, Line: 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := mylog.CreateLogger(true)
+			l := lexer.New(log, tt.input)
+			p := New(l)
+			jf, jErr := p.ParseFile()
+
+			assert.Empty(t, jf, "jsonFile should be empty")
+			assert.Equal(t, &JSONErr{Msg: "Unexpected trailing content after JSON value\n", Pos: tt.expectedPos}, jErr)
+		})
+	}
+}
+
+func TestTrailingWhitespace(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		opts        []lexer.Option
+		expected    interface{}
+		expectedEOF token.Position
+	}{
+		{
+			name:        "Blank Lines And Spaces After Object",
+			input:       "{\"a\":1}\n\n  ",
+			expected:    map[string]interface{}{"a": float64(1)},
+			expectedEOF: token.Position{Column: 3, Line: 3},
+		},
+		{
+			name:        "Single Newline After Array",
+			input:       "[1, 2]\n",
+			expected:    []interface{}{float64(1), float64(2)},
+			expectedEOF: token.Position{Column: 1, Line: 2},
+		},
+		{
+			name:        "CRLF And Tabs After Array",
+			input:       "[\r\n]\r\n\t \r\n",
+			expected:    []interface{}{},
+			expectedEOF: token.Position{Column: 1, Line: 4},
+		},
+		{
+			name:        "Whitespace Around Object",
+			input:       " \n\t{}\t\n ",
+			expected:    map[string]interface{}{},
+			expectedEOF: token.Position{Column: 2, Line: 3},
+		},
+		{
+			name:        "Trailing Comment And Newlines",
+			input:       "{\"a\":1} // done\n\n",
+			opts:        []lexer.Option{lexer.WithComments()},
+			expected:    map[string]interface{}{"a": float64(1)},
+			expectedEOF: token.Position{Column: 1, Line: 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New(lexer.New(nil, tt.input, tt.opts...))
+			jf, jErr := p.ParseFile()
+
+			require.Nil(t, jErr, "jsonErr should be empty")
+			assert.Equal(t, tt.expected, jf.ToInterface())
+			assert.Equal(t, token.Token{Type: token.EOF, Position: tt.expectedEOF}, p.Current())
+		})
+	}
+}
+
+func TestPeekAndCurrent(t *testing.T) {
+	log := mylog.CreateLogger(true)
+	l := lexer.New(log, `{"key": [1]}`)
+	p := New(l)
+
+	assert.Equal(t, p.curToken, p.Current())
+	assert.Equal(t, p.peekToken, p.Peek())
+	assert.Equal(t, token.Token{Type: token.LBRACE, Literal: "{", Position: token.Position{Line: 1, Column: 1}},
+		p.Current())
+	assert.Equal(t, token.Token{Type: token.STRING, Literal: "key", Position: token.Position{Line: 1, Column: 2}},
+		p.Peek())
+
+	peeked := p.Peek()
+	assert.Equal(t, peeked, p.Peek(), "Peek() should not consume the token")
+	assert.Equal(t, token.TokenType(token.LBRACE), p.Current().Type, "Peek() should not advance Current()")
+
+	jf, jErr := p.ParseFile()
+	require.Empty(t, jErr, "jsonErr should be empty")
+	assert.Equal(t, map[string]interface{}{"key": []interface{}{float64(1)}}, jf.ToInterface())
+	assert.Equal(t, token.TokenType(token.EOF), p.Current().Type)
+}
+
+func TestParseArray(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []interface{}
+	}{
+		{
+			name:     "Empty Array",
+			input:    `[]`,
+			expected: []interface{}{},
+		},
+		{
+			name:     "Array Of Integers",
+			input:    `[1, 2, 3]`,
+			expected: []interface{}{1, 2, 3},
+		},
+		{
+			name:     "Array Of Strings",
+			input:    `["x", "y", "z"]`,
+			expected: []interface{}{"x", "y", "z"},
+		},
+		{
+			name:     "Array Of Booleans And Null",
 			input:    `[true, false, null]`,
 			expected: []interface{}{true, false, nil},
 		},
@@ -732,6 +1625,165 @@ func TestParseNumber(t *testing.T) {
 	}
 }
 
+func TestParseNumberRange(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		opts        []Option
+		expected    float64
+		expectedErr *JSONErr
+	}{
+		{
+			name:  "Overflow",
+			input: "1e400",
+			expectedErr: &JSONErr{
+				Msg: "Number \"1e400\" overflows float64 range\n",
+				Pos: token.Position{
+					Column: 1,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:  "Negative Overflow",
+			input: "-1.5e309",
+			expectedErr: &JSONErr{
+				Msg: "Number \"-1.5e309\" overflows float64 range\n",
+				Pos: token.Position{
+					Column: 1,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:     "Underflow To Zero",
+			input:    "1e-400",
+			expected: 0,
+		},
+		{
+			name:     "Clamped Overflow",
+			input:    "1e400",
+			opts:     []Option{ClampNumbers()},
+			expected: math.MaxFloat64,
+		},
+		{
+			name:     "Clamped Negative Overflow",
+			input:    "-1e400",
+			opts:     []Option{ClampNumbers()},
+			expected: -math.MaxFloat64,
+		},
+		{
+			name:     "Largest Float64",
+			input:    "1.7976931348623157e308",
+			expected: math.MaxFloat64,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := mylog.CreateLogger(true)
+			l := lexer.New(log, tt.input)
+			p := New(l, tt.opts...)
+			actual, jsonErr := p.parseNumber()
+
+			if tt.expectedErr != nil {
+				assert.Nil(t, actual)
+				assert.Equal(t, tt.expectedErr, jsonErr)
+				return
+			}
+			require.Empty(t, jsonErr, "jsonErr should be empty")
+			assert.Equal(t, tt.expected, actual.(*ast.NumberLiteral).Value)
+		})
+	}
+}
+
+func TestParseBigNumber(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    string
+		expectedErr *JSONErr
+	}{
+		{
+			name:     "One Tenth",
+			input:    "0.1",
+			expected: "1/10",
+		},
+		{
+			name:     "Decimal With Exponent",
+			input:    "-0.3e1",
+			expected: "-3",
+		},
+		{
+			name:     "Large Integer Beyond Float64 Precision",
+			input:    "12345678901234567890123",
+			expected: "12345678901234567890123",
+		},
+		{
+			name:     "Number Beyond Float64 Range",
+			input:    "1e400",
+			expected: "1" + strings.Repeat("0", 400),
+		},
+		{
+			name:     "Number Below Float64 Range",
+			input:    "1e-400",
+			expected: "1/1" + strings.Repeat("0", 400),
+		},
+		{
+			name:  "Exponent Too Large",
+			input: "1e999999999",
+			expectedErr: &JSONErr{
+				Msg: "Exponent of \"1e999999999\" is out of range for big numbers\n",
+				Pos: token.Position{
+					Column: 1,
+					Line:   1,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := mylog.CreateLogger(true)
+			l := lexer.New(log, string(tt.input))
+			p := New(l, WithBigNumbers())
+			actual, jsonErr := p.parseNumber()
+
+			if tt.expectedErr != nil {
+				assert.Empty(t, actual, "number should be empty")
+				assert.Equal(t, tt.expectedErr, jsonErr)
+				return
+			}
+			require.Empty(t, jsonErr, "jsonErr should be empty")
+
+			num, ok := actual.(*ast.NumberLiteral)
+			require.True(t, ok, "num not *ast.NumberLiteral. got=%T", actual)
+			require.NotNil(t, num.Big, "num.Big should be set")
+			assert.Equal(t, tt.expected, num.Big.RatString())
+			assert.Equal(t, num.Big, num.ToInterface())
+		})
+	}
+}
+
+func TestBigNumbersExactArithmetic(t *testing.T) {
+	input := `{"a": 0.1, "b": 0.2, "sum": 0.3, "price": 19.99, "qty": 3}`
+	log := mylog.CreateLogger(true)
+	l := lexer.New(log, input)
+	p := New(l, WithBigNumbers())
+	jf, jsonErr := p.ParseFile()
+	require.Empty(t, jsonErr, "jsonErr should be empty")
+
+	values := jf.ToInterface().(map[string]interface{})
+	a := values["a"].(*big.Rat)
+	b := values["b"].(*big.Rat)
+	sum := values["sum"].(*big.Rat)
+	price := values["price"].(*big.Rat)
+	qty := values["qty"].(*big.Rat)
+
+	assert.Equal(t, 0, new(big.Rat).Add(a, b).Cmp(sum), "0.1 + 0.2 should equal 0.3 exactly")
+	assert.Equal(t, "59.97", new(big.Rat).Mul(price, qty).FloatString(2))
+}
+
 func assertArrayLiteral(t *testing.T, actual ast.Element, expected []interface{}) bool {
 	al, ok := actual.(*ast.ArrayLiteral)
 	if !ok {
@@ -922,3 +1974,1376 @@ func assertNumberLiteral(t *testing.T, actual ast.Element, expected float64) boo
 
 	return true
 }
+
+func TestMaxDepth(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		opts        []Option
+		expectedErr *JSONErr
+	}{
+		{
+			name:  "Nesting Within Limit",
+			input: `{"key": [[1]]}`,
+			opts:  []Option{WithMaxDepth(3)},
+		},
+		{
+			name:  "Nesting Exceeds Limit",
+			input: `{"key": [[1]]}`,
+			opts:  []Option{WithMaxDepth(2)},
+			expectedErr: &JSONErr{
+				Msg: "Maximum nesting depth of 2 exceeded\n",
+				Pos: token.Position{
+					Column: 10,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:  "Nesting Exceeds Default Limit",
+			input: strings.Repeat("[", DefaultMaxDepth+1) + strings.Repeat("]", DefaultMaxDepth+1),
+			expectedErr: &JSONErr{
+				Msg: fmt.Sprintf("Maximum nesting depth of %d exceeded\n", DefaultMaxDepth),
+				Pos: token.Position{
+					Column: DefaultMaxDepth + 1,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:  "Disabled Limit",
+			input: strings.Repeat("[", DefaultMaxDepth+1) + strings.Repeat("]", DefaultMaxDepth+1),
+			opts:  []Option{WithMaxDepth(0)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := mylog.CreateLogger(false)
+			l := lexer.New(log, tt.input)
+			p := New(l, tt.opts...)
+			jf, jErr := p.ParseFile()
+
+			if tt.expectedErr != nil {
+				assert.Empty(t, jf, "jsonFile should be empty")
+				assert.Equal(t, tt.expectedErr, jErr)
+				return
+			}
+			require.Empty(t, jErr, "jsonErr should be empty")
+			assert.Len(t, jf.Elements, 1, "length of elements isn't correct")
+		})
+	}
+}
+
+func TestMaxElements(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		opts        []Option
+		expectedErr *JSONErr
+	}{
+		{
+			name:  "Elements Within Limit",
+			input: `{"a": [1, 2, 3], "b": {"c": 1, "d": 2}, "e": []}`,
+			opts:  []Option{WithMaxElements(3)},
+		},
+		{
+			name:  "Array Exceeds Limit",
+			input: `{"a": [1, 2, 3, 4]}`,
+			opts:  []Option{WithMaxElements(3)},
+			expectedErr: &JSONErr{
+				Msg: "Maximum of 3 elements per array exceeded\n",
+				Pos: token.Position{
+					Column: 17,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:  "Object Exceeds Limit",
+			input: `[{"a": 1, "b": 2, "c": 3}]`,
+			opts:  []Option{WithMaxElements(2)},
+			expectedErr: &JSONErr{
+				Msg: "Maximum of 2 elements per object exceeded\n",
+				Pos: token.Position{
+					Column: 19,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:  "Disabled Limit",
+			input: "[" + strings.Repeat("1, ", 100) + "1]",
+			opts:  []Option{WithMaxElements(0)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := mylog.CreateLogger(false)
+			l := lexer.New(log, tt.input)
+			p := New(l, tt.opts...)
+			jf, jErr := p.ParseFile()
+
+			if tt.expectedErr != nil {
+				assert.Empty(t, jf, "jsonFile should be empty")
+				assert.Equal(t, tt.expectedErr, jErr)
+				return
+			}
+			require.Empty(t, jErr, "jsonErr should be empty")
+			assert.Len(t, jf.Elements, 1, "length of elements isn't correct")
+		})
+	}
+}
+
+func TestAllowExtendedEscapes(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		opts        []Option
+		expected    string
+		expectedErr *JSONErr
+	}{
+		{
+			name:     "Hex Escape",
+			input:    `["\x41BC"]`,
+			opts:     []Option{AllowExtendedEscapes()},
+			expected: "ABC",
+		},
+		{
+			name:     "NUL Escape",
+			input:    `["a\0b"]`,
+			opts:     []Option{AllowExtendedEscapes()},
+			expected: "a\x00b",
+		},
+		{
+			name:     "Standard Escapes Still Decoded",
+			input:    `["\x7e\n\u00e9"]`,
+			opts:     []Option{AllowExtendedEscapes()},
+			expected: "~\né",
+		},
+		{
+			name:  "Invalid Hex Escape",
+			input: `["\x4g"]`,
+			opts:  []Option{AllowExtendedEscapes()},
+			expectedErr: &JSONErr{
+				Msg: "Invalid hex escape sequence\n",
+				Pos: token.Position{
+					Column: 2,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:  "Strict Hex Escape",
+			input: `["\x41"]`,
+			expectedErr: &JSONErr{
+				Msg: "Invalid escape sequence\n",
+				Pos: token.Position{
+					Column: 2,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:  "Strict NUL Escape",
+			input: `["\0"]`,
+			expectedErr: &JSONErr{
+				Msg: "Invalid escape sequence\n",
+				Pos: token.Position{
+					Column: 2,
+					Line:   1,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := mylog.CreateLogger(true)
+			l := lexer.New(log, tt.input)
+			p := New(l, tt.opts...)
+			jf, jErr := p.ParseFile()
+
+			if tt.expectedErr != nil {
+				assert.Empty(t, jf, "jsonFile should be empty")
+				assert.Equal(t, tt.expectedErr, jErr)
+				return
+			}
+			require.Empty(t, jErr, "jsonErr should be empty")
+
+			sl, ok := jf.Elements[0].(*ast.ArrayLiteral).Elements[0].(*ast.StringLiteral)
+			require.True(t, ok, "element should be *ast.StringLiteral")
+
+			str, err := sl.Unescaped()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, str)
+		})
+	}
+}
+
+func TestAllowBraceUnicode(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		opts        []Option
+		expected    string
+		expectedErr *JSONErr
+	}{
+		{
+			name:     "Basic Multilingual Plane",
+			input:    `["\u{41}"]`,
+			opts:     []Option{AllowBraceUnicode()},
+			expected: "A",
+		},
+		{
+			name:     "Astral Character",
+			input:    `["\u{1F600}!"]`,
+			opts:     []Option{AllowBraceUnicode()},
+			expected: "😀!",
+		},
+		{
+			name:     "Standard Escapes Still Decoded",
+			input:    `["\u00e9\u{e9}\ud83d\ude00"]`,
+			opts:     []Option{AllowBraceUnicode()},
+			expected: "éé😀",
+		},
+		{
+			name:  "Too Many Digits",
+			input: `["\u{0000041}"]`,
+			opts:  []Option{AllowBraceUnicode()},
+			expectedErr: &JSONErr{
+				Msg: "Invalid unicode escape sequence\n",
+				Pos: token.Position{
+					Column: 2,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:  "Surrogate",
+			input: `["\u{D800}"]`,
+			opts:  []Option{AllowBraceUnicode()},
+			expectedErr: &JSONErr{
+				Msg: "Invalid unicode escape sequence\n",
+				Pos: token.Position{
+					Column: 2,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:  "Beyond Unicode",
+			input: `["\u{110000}"]`,
+			opts:  []Option{AllowBraceUnicode()},
+			expectedErr: &JSONErr{
+				Msg: "Invalid unicode escape sequence\n",
+				Pos: token.Position{
+					Column: 2,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:  "Strict Mode",
+			input: `["\u{41}"]`,
+			expectedErr: &JSONErr{
+				Msg: "Invalid unicode escape sequence\n",
+				Pos: token.Position{
+					Column: 2,
+					Line:   1,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jf, jErr := ParseString(tt.input, tt.opts...)
+
+			if tt.expectedErr != nil {
+				assert.Empty(t, jf, "jsonFile should be empty")
+				assert.Equal(t, tt.expectedErr, jErr)
+				return
+			}
+			require.Empty(t, jErr, "jsonErr should be empty")
+
+			sl, ok := jf.Elements[0].(*ast.ArrayLiteral).Elements[0].(*ast.StringLiteral)
+			require.True(t, ok, "element should be *ast.StringLiteral")
+
+			str, err := sl.Unescaped()
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, str)
+		})
+	}
+}
+
+func TestAllowUndefined(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		opts        []Option
+		expected    string
+		expectedErr *JSONErr
+	}{
+		{
+			name:     "Object Value",
+			input:    `{"a": undefined}`,
+			opts:     []Option{AllowUndefined()},
+			expected: `{"a":null}`,
+		},
+		{
+			name:     "Array Elements",
+			input:    `[1, undefined, null]`,
+			opts:     []Option{AllowUndefined()},
+			expected: `[1,null,null]`,
+		},
+		{
+			name:  "Strict Object Value",
+			input: `{"a": undefined}`,
+			expectedErr: &JSONErr{
+				Msg: "Expected 'STRING', 'NUMBER', 'NULL', 'TRUE', 'FALSE', '{', '[', got 'ILLEGAL' instead\n",
+				Pos: token.Position{
+					Column: 7,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:  "Strict Array Element",
+			input: `[undefined]`,
+			expectedErr: &JSONErr{
+				Msg: "Expected 'STRING', 'NUMBER', 'NULL', 'TRUE', 'FALSE', '{', '[', ']', got 'ILLEGAL' instead\n",
+				Pos: token.Position{
+					Column: 2,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:  "Strict Key",
+			input: `{undefined: 1}`,
+			expectedErr: &JSONErr{
+				Msg: "Unquoted object key; keys must be double-quoted strings in JSON\n",
+				Pos: token.Position{
+					Column: 2,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:  "Not A Key",
+			input: `{undefined: 1}`,
+			opts:  []Option{AllowUndefined()},
+			expectedErr: &JSONErr{
+				Msg: "Unquoted object key; keys must be double-quoted strings in JSON\n",
+				Pos: token.Position{
+					Column: 2,
+					Line:   1,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := mylog.CreateLogger(true)
+			l := lexer.New(log, tt.input)
+			p := New(l, tt.opts...)
+			jf, jErr := p.ParseFile()
+
+			if tt.expectedErr != nil {
+				assert.Empty(t, jf, "jsonFile should be empty")
+				assert.Equal(t, tt.expectedErr, jErr)
+				return
+			}
+			require.Empty(t, jErr, "jsonErr should be empty")
+
+			out, err := ast.Marshal(jf.Elements[0])
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(out))
+		})
+	}
+}
+
+func TestWithIntegerDetection(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		opts     []Option
+		expected interface{}
+	}{
+		{name: "Integer", input: `[42]`, opts: []Option{WithIntegerDetection()}, expected: int64(42)},
+		{name: "Negative Integer", input: `[-7]`, opts: []Option{WithIntegerDetection()}, expected: int64(-7)},
+		{name: "Integral Decimal", input: `[42.0]`, opts: []Option{WithIntegerDetection()}, expected: 42.0},
+		{name: "Exponent", input: `[1e2]`, opts: []Option{WithIntegerDetection()}, expected: 100.0},
+		{name: "Fraction", input: `[42.5]`, opts: []Option{WithIntegerDetection()}, expected: 42.5},
+		{
+			name:     "Max Int64",
+			input:    `[9223372036854775807]`,
+			opts:     []Option{WithIntegerDetection()},
+			expected: int64(math.MaxInt64),
+		},
+		{
+			name:     "Huge Integer Falls Back To Float",
+			input:    `[123456789012345678901234567890]`,
+			opts:     []Option{WithIntegerDetection()},
+			expected: 1.2345678901234568e29,
+		},
+		{
+			name:     "Huge Integer Falls Back To Big",
+			input:    `[123456789012345678901234567890]`,
+			opts:     []Option{WithIntegerDetection(), WithBigNumbers()},
+			expected: new(big.Rat).SetFrac(mustBigInt(t, "123456789012345678901234567890"), big.NewInt(1)),
+		},
+		{name: "Disabled", input: `[42]`, expected: 42.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jf, jErr := ParseString(tt.input, tt.opts...)
+			require.Empty(t, jErr, "jsonErr should be empty")
+
+			values := jf.ToInterface().([]interface{})
+			assert.Equal(t, tt.expected, values[0])
+		})
+	}
+}
+
+func mustBigInt(t *testing.T, s string) *big.Int {
+	t.Helper()
+
+	n, ok := new(big.Int).SetString(s, 10)
+	require.True(t, ok, "invalid big integer %q", s)
+	return n
+}
+
+func TestParseHexNumber(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		lexerOpts   []lexer.Option
+		expected    interface{}
+		expectedErr *JSONErr
+	}{
+		{
+			name:      "Hex Array",
+			input:     `[0x1F, 0Xabc, -0x10]`,
+			lexerOpts: []lexer.Option{lexer.AllowHexNumbers()},
+			expected:  []interface{}{float64(31), float64(2748), float64(-16)},
+		},
+		{
+			name:      "Hex Object Value",
+			input:     `{"mask": 0xFF}`,
+			lexerOpts: []lexer.Option{lexer.AllowHexNumbers()},
+			expected:  map[string]interface{}{"mask": float64(255)},
+		},
+		{
+			name:  "Strict Mode",
+			input: `[0x1F]`,
+			expectedErr: &JSONErr{
+				Msg: "Expected ',', ']'. got 'ILLEGAL' instead\n",
+				Pos: token.Position{
+					Column: 3,
+					Line:   1,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := mylog.CreateLogger(true)
+			l := lexer.New(log, tt.input, tt.lexerOpts...)
+			p := New(l)
+			jf, jErr := p.ParseFile()
+
+			if tt.expectedErr != nil {
+				assert.Empty(t, jf, "jsonFile should be empty")
+				assert.Equal(t, tt.expectedErr, jErr)
+				return
+			}
+			require.Empty(t, jErr, "jsonErr should be empty")
+			assert.Equal(t, tt.expected, jf.ToInterface())
+		})
+	}
+}
+
+func TestParseHexNumberIntegerAndMarshal(t *testing.T) {
+	log := mylog.CreateLogger(true)
+	l := lexer.New(log, `[-0x10, 0x7FFFFFFFFFFFFFFF]`, lexer.AllowHexNumbers())
+	p := New(l, WithBigNumbers())
+	jf, jErr := p.ParseFile()
+	require.Empty(t, jErr, "jsonErr should be empty")
+
+	elements := jf.Elements[0].(*ast.ArrayLiteral).Elements
+	n, ok := elements[0].(*ast.NumberLiteral).Int64()
+	assert.True(t, ok)
+	assert.Equal(t, int64(-16), n)
+	assert.Equal(t, "9223372036854775807", elements[1].(*ast.NumberLiteral).Big.RatString())
+
+	out, err := ast.Marshal(jf.Elements[0])
+	require.NoError(t, err)
+	assert.Equal(t, `[-16,9223372036854775807]`, string(out))
+}
+
+func TestParseLeadingPlus(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		lexerOpts   []lexer.Option
+		opts        []Option
+		expected    interface{}
+		expectedErr *JSONErr
+	}{
+		{
+			name:      "Signed Numbers",
+			input:     `[+123, +1.5e2, -4]`,
+			lexerOpts: []lexer.Option{lexer.AllowLeadingPlus()},
+			expected:  []interface{}{float64(123), float64(150), float64(-4)},
+		},
+		{
+			name:      "Signed Object Value",
+			input:     `{"delta": +0.5}`,
+			lexerOpts: []lexer.Option{lexer.AllowLeadingPlus()},
+			expected:  map[string]interface{}{"delta": 0.5},
+		},
+		{
+			name:     "Parser Option",
+			input:    `[+123, +1.5e2]`,
+			opts:     []Option{AllowLeadingPlus()},
+			expected: []interface{}{float64(123), float64(150)},
+		},
+		{
+			name:  "Strict Mode",
+			input: `[+123]`,
+			expectedErr: &JSONErr{
+				Msg: "Expected 'STRING', 'NUMBER', 'NULL', 'TRUE', 'FALSE', '{', '[', ']', got 'ILLEGAL' instead\n",
+				Pos: token.Position{
+					Column: 2,
+					Line:   1,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := mylog.CreateLogger(true)
+			l := lexer.New(log, tt.input, tt.lexerOpts...)
+			p := New(l, tt.opts...)
+			jf, jErr := p.ParseFile()
+
+			if tt.expectedErr != nil {
+				assert.Empty(t, jf, "jsonFile should be empty")
+				assert.Equal(t, tt.expectedErr, jErr)
+				return
+			}
+			require.Empty(t, jErr, "jsonErr should be empty")
+			assert.Equal(t, tt.expected, jf.ToInterface())
+		})
+	}
+}
+
+func TestParseLeadingPlusIntegerAndMarshal(t *testing.T) {
+	log := mylog.CreateLogger(true)
+	l := lexer.New(log, `[+42, +1.5e2]`, lexer.AllowLeadingPlus())
+	p := New(l)
+	jf, jErr := p.ParseFile()
+	require.Empty(t, jErr, "jsonErr should be empty")
+
+	elements := jf.Elements[0].(*ast.ArrayLiteral).Elements
+	n, ok := elements[0].(*ast.NumberLiteral).Int64()
+	assert.True(t, ok)
+	assert.Equal(t, int64(42), n)
+
+	out, err := ast.Marshal(jf.Elements[0])
+	require.NoError(t, err)
+	assert.Equal(t, `[42,1.5e2]`, string(out))
+}
+
+func TestParseWithTokens(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "Empty Object", input: `{}`},
+		{name: "Nested Values", input: `{"a": [1, -2.5e3, true], "b": {"c": null}}`},
+		{name: "Multiline Array", input: "[\n  \"x\",\n  false\n]\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected, lexErr := lexer.Tokenize(nil, tt.input)
+			require.Nil(t, lexErr)
+
+			jf, tokens, jErr := ParseWithTokens(lexer.New(nil, tt.input))
+
+			require.Nil(t, jErr, "jsonErr should be empty")
+			assert.NotNil(t, jf)
+			assert.Equal(t, expected, tokens)
+		})
+	}
+}
+
+func TestParseWithTokensComments(t *testing.T) {
+	input := "// head\n{\"a\": 1 /* one */}"
+	jf, tokens, jErr := ParseWithTokens(lexer.New(nil, input, lexer.WithComments()))
+
+	require.Nil(t, jErr, "jsonErr should be empty")
+	assert.Equal(t, `{"a":1}`, jf.String())
+
+	types := []token.TokenType{}
+	for _, tok := range tokens {
+		types = append(types, tok.Type)
+	}
+	assert.Equal(t, []token.TokenType{
+		token.COMMENT, token.LBRACE, token.STRING, token.COLON, token.NUMBER,
+		token.COMMENT, token.RBRACE, token.EOF,
+	}, types)
+}
+
+func TestParseWithTokensError(t *testing.T) {
+	input := `{"a": 1,}`
+	jf, tokens, jErr := ParseWithTokens(lexer.New(nil, input))
+
+	require.NotNil(t, jErr)
+	assert.Nil(t, jf)
+
+	expected, _ := lexer.Tokenize(nil, input)
+	assert.Equal(t, expected[:len(tokens)], tokens)
+}
+
+func TestParseValueAndRest(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		expected     string
+		expectedRest string
+	}{
+		{
+			name:         "Trailing Text",
+			input:        `{"a":1} trailing`,
+			expected:     `{"a":1}`,
+			expectedRest: "trailing",
+		},
+		{
+			name:         "Trailing Newlines",
+			input:        "[1, 2]\n\n  next line",
+			expected:     "[1, 2]",
+			expectedRest: "next line",
+		},
+		{
+			name:         "No Trailing Input",
+			input:        `{"a": [true]}   `,
+			expected:     `{"a":[true]}`,
+			expectedRest: "",
+		},
+		{
+			name:         "Another Value Follows",
+			input:        `[1]{"b": null}`,
+			expected:     "[1]",
+			expectedRest: `{"b": null}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := mylog.CreateLogger(true)
+			l := lexer.New(log, tt.input)
+			p := New(l)
+			elem, rest, jErr := p.ParseValueAndRest()
+
+			require.Empty(t, jErr, "jsonErr should be empty")
+			assert.Equal(t, tt.expected, elem.String())
+			assert.Equal(t, tt.expectedRest, tt.input[rest:])
+		})
+	}
+}
+
+func TestParseValueAndRestSequence(t *testing.T) {
+	input := `{"a": 1} [2] oops`
+	log := mylog.CreateLogger(true)
+	l := lexer.New(log, input)
+	p := New(l)
+
+	elem, rest, jErr := p.ParseValueAndRest()
+	require.Empty(t, jErr, "jsonErr should be empty")
+	assert.Equal(t, `{"a":1}`, elem.String())
+	assert.Equal(t, 9, rest)
+
+	elem, rest, jErr = p.ParseValueAndRest()
+	require.Empty(t, jErr, "jsonErr should be empty")
+	assert.Equal(t, "[2]", elem.String())
+	assert.Equal(t, 13, rest)
+
+	elem, _, jErr = p.ParseValueAndRest()
+	assert.Nil(t, elem)
+	assert.Equal(t, &JSONErr{
+		Msg: "Expected '{' or '[', got 'ILLEGAL' instead\n",
+		Pos: token.Position{
+			Column: 14,
+			Line:   1,
+		},
+	}, jErr)
+}
+
+func TestWithDuplicateKeys(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		opts        []Option
+		expected    string
+		expectedErr *JSONErr
+	}{
+		{
+			name:  "Error By Default",
+			input: `{"a": 1, "b": 2, "a": 3}`,
+			expectedErr: &JSONErr{
+				Msg: "Duplicate JSON property '\"a\"'\n",
+				Pos: token.Position{
+					Column: 21,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:  "Escaped Duplicate",
+			input: `{"a": 1, "\u0061": 2}`,
+			expectedErr: &JSONErr{
+				Msg: "Duplicate JSON property '\"\\u0061\"'\n",
+				Pos: token.Position{
+					Column: 18,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:     "Escaped Duplicate Keep Last",
+			input:    `{"a": 1, "\u0061": 2}`,
+			opts:     []Option{WithDuplicateKeys(DuplicateKeysLast)},
+			expected: `{"a":2}`,
+		},
+		{
+			name:     "Keep First",
+			input:    `{"a": 1, "b": 2, "a": [3]}`,
+			opts:     []Option{WithDuplicateKeys(DuplicateKeysFirst)},
+			expected: `{"a":1,"b":2}`,
+		},
+		{
+			name:     "Keep Last",
+			input:    `{"a": 1, "b": 2, "a": [3], "a": {"c": 4}}`,
+			opts:     []Option{WithDuplicateKeys(DuplicateKeysLast)},
+			expected: `{"a":{"c":4},"b":2}`,
+		},
+		{
+			name:  "Invalid Duplicate Value Still Rejected",
+			input: `{"a": 1, "a": tru}`,
+			opts:  []Option{WithDuplicateKeys(DuplicateKeysFirst)},
+			expectedErr: &JSONErr{
+				Msg: "Expected 'STRING', 'NUMBER', 'NULL', 'TRUE', 'FALSE', '{', '[', got 'ILLEGAL' instead\n",
+				Pos: token.Position{
+					Column: 15,
+					Line:   1,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := mylog.CreateLogger(true)
+			l := lexer.New(log, tt.input)
+			p := New(l, tt.opts...)
+			jf, jErr := p.ParseFile()
+
+			if tt.expectedErr != nil {
+				assert.Empty(t, jf, "jsonFile should be empty")
+				assert.Equal(t, tt.expectedErr, jErr)
+				return
+			}
+			require.Empty(t, jErr, "jsonErr should be empty")
+
+			out, err := ast.Marshal(jf.Elements[0])
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(out))
+		})
+	}
+}
+
+func TestParseFileReportingDuplicates(t *testing.T) {
+	input := `{
+  "a": 1,
+  "b": {"c": true, "c": false},
+  "a": 2,
+  "a": 3
+}`
+
+	log := mylog.CreateLogger(true)
+	l := lexer.New(log, input)
+	p := New(l)
+	reports, jf, jErr := p.ParseFileReportingDuplicates()
+	require.Empty(t, jErr, "jsonErr should be empty")
+
+	expected := []DuplicateReport{
+		{
+			Key:       "c",
+			First:     token.Position{Line: 3, Column: 9},
+			Duplicate: token.Position{Line: 3, Column: 20},
+		},
+		{
+			Key:       "a",
+			First:     token.Position{Line: 2, Column: 3},
+			Duplicate: token.Position{Line: 4, Column: 3},
+		},
+		{
+			Key:       "a",
+			First:     token.Position{Line: 2, Column: 3},
+			Duplicate: token.Position{Line: 5, Column: 3},
+		},
+	}
+	assert.Equal(t, expected, reports)
+
+	out, err := ast.Marshal(jf.Elements[0])
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":3,"b":{"c":false}}`, string(out))
+
+	p.Reset(lexer.New(log, `{"a": 1, "a": 2}`))
+	_, jErr = p.ParseFile()
+	require.NotNil(t, jErr, "the parser's own policy should apply again")
+	assert.Equal(t, "Duplicate JSON property '\"a\"'\n", jErr.Msg)
+}
+
+func TestResetAfterError(t *testing.T) {
+	p := New(lexer.New(nil, `{"a": [1, }`), LintNumbers())
+	_, jErr := p.ParseFile()
+	require.NotNil(t, jErr)
+
+	p.Reset(lexer.New(nil, `{"b": 1.50}`))
+	jf, jErr := p.ParseFile()
+	require.Nil(t, jErr, "the first document's error should not leak")
+	assert.Equal(t, map[string]interface{}{"b": 1.5}, jf.ToInterface())
+	assert.Equal(t, &JSONErr{}, p.JSONErr)
+	assert.Len(t, p.Warnings(), 1)
+
+	p.Reset(lexer.New(nil, `[true]`))
+	jf, jErr = p.ParseFile()
+	require.Nil(t, jErr)
+	assert.Equal(t, []interface{}{true}, jf.ToInterface())
+	assert.Empty(t, p.Warnings(), "warnings should not carry over")
+}
+
+func TestParseFileReportingDuplicatesTwoKeys(t *testing.T) {
+	p := New(lexer.New(nil, `{"x": 1, "y": 2, "x": 3, "y": 4}`))
+	reports, _, jErr := p.ParseFileReportingDuplicates()
+	require.Empty(t, jErr, "jsonErr should be empty")
+
+	require.Len(t, reports, 2)
+	assert.Equal(t, "x", reports[0].Key)
+	assert.Equal(t, "y", reports[1].Key)
+}
+
+func TestDuplicatesNotRecordedWithoutReporting(t *testing.T) {
+	p := New(lexer.New(nil, `{"x": 1, "x": 2}`), WithDuplicateKeys(DuplicateKeysLast))
+	_, jErr := p.ParseFile()
+	require.Nil(t, jErr)
+	assert.Empty(t, p.duplicates, "ParseFile should not collect reports")
+}
+
+func TestTabWidthErrorPosition(t *testing.T) {
+	l := lexer.New(mylog.CreateLogger(true), "{\n\t\"key\":\t}", lexer.WithTabWidth(4))
+	_, jErr := New(l).ParseFile()
+
+	expected := &JSONErr{
+		Msg: "Missing value for key 'key'\n",
+		Pos: token.Position{
+			Column: 15,
+			Line:   2,
+		},
+	}
+	assert.Equal(t, expected, jErr)
+}
+
+func TestEmptyInputRootOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{name: "Scalar Root", opts: []Option{AllowScalarRoot()}},
+		{name: "Allowed Roots", opts: []Option{WithAllowedRoots(token.LBRACE)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jf, jErr := ParseString("\n", tt.opts...)
+
+			assert.Nil(t, jf)
+			assert.Equal(t, &JSONErr{
+				Msg: "Unexpected end of input, expected a JSON value\n",
+				Pos: token.Position{Column: 1, Line: 2},
+			}, jErr)
+		})
+	}
+}
+
+func TestAllowEmptyInput(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		opts        []Option
+		expectedErr *JSONErr
+	}{
+		{
+			name:  "Empty Input Strict",
+			input: ``,
+			expectedErr: &JSONErr{
+				Msg: "Unexpected end of input, expected a JSON value\n",
+				Pos: token.Position{
+					Column: 1,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:  "Whitespace Only Input Strict",
+			input: " \n\t\r\n ",
+			expectedErr: &JSONErr{
+				Msg: "Unexpected end of input, expected a JSON value\n",
+				Pos: token.Position{
+					Column: 2,
+					Line:   3,
+				},
+			},
+		},
+		{
+			name:  "Empty Input Allowed",
+			input: ``,
+			opts:  []Option{AllowEmptyInput()},
+		},
+		{
+			name:  "Whitespace Only Input Allowed",
+			input: " \n\t\r\n ",
+			opts:  []Option{AllowEmptyInput()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := mylog.CreateLogger(true)
+			l := lexer.New(log, tt.input)
+			p := New(l, tt.opts...)
+			jf, jErr := p.ParseFile()
+
+			if tt.expectedErr != nil {
+				assert.Empty(t, jf, "jsonFile should be empty")
+				assert.Equal(t, tt.expectedErr, jErr)
+				return
+			}
+			require.Empty(t, jErr, "jsonErr should be empty")
+			require.NotNil(t, jf, "jsonFile should not be nil")
+			assert.Empty(t, jf.Elements, "elements should be empty")
+			assert.Nil(t, jf.ToInterface())
+		})
+	}
+}
+
+func TestParseConcatenated(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    []interface{}
+		expectedErr *JSONErr
+	}{
+		{
+			name:  "Objects Without Separator",
+			input: `{"a":1}{"b":2}`,
+			expected: []interface{}{
+				map[string]interface{}{"a": float64(1)},
+				map[string]interface{}{"b": float64(2)},
+			},
+		},
+		{
+			name:  "Arrays Separated By Whitespace",
+			input: "[1] [2]\n",
+			expected: []interface{}{
+				[]interface{}{float64(1)},
+				[]interface{}{float64(2)},
+			},
+		},
+		{
+			name:  "Mixed Values Across Lines",
+			input: "{\"a\": [true]}\n\t[null, {}]",
+			expected: []interface{}{
+				map[string]interface{}{"a": []interface{}{true}},
+				[]interface{}{nil, map[string]interface{}{}},
+			},
+		},
+		{
+			name:     "Empty Input",
+			input:    ``,
+			expected: []interface{}{},
+		},
+		{
+			name:  "Malformed Second Value",
+			input: `{"a":1}{"b":}`,
+			expectedErr: &JSONErr{
+				Msg: "Missing value for key 'b'\n",
+				Pos: token.Position{
+					Column: 13,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:  "Scalar Between Values",
+			input: `[1] 2 [3]`,
+			expectedErr: &JSONErr{
+				Msg: "Expected '{' or '[', got 'NUMBER' instead\n",
+				Pos: token.Position{
+					Column: 5,
+					Line:   1,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := mylog.CreateLogger(true)
+			l := lexer.New(log, tt.input)
+			docs, jErr := ParseConcatenated(l)
+
+			if tt.expectedErr != nil {
+				assert.Empty(t, docs, "docs should be empty")
+				assert.Equal(t, tt.expectedErr, jErr)
+				return
+			}
+			require.Empty(t, jErr, "jsonErr should be empty")
+
+			actual := []interface{}{}
+			for _, doc := range docs {
+				actual = append(actual, doc.ToInterface())
+			}
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestMaxStringLength(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expectedErr *JSONErr
+	}{
+		{
+			name:  "Value Exceeding Limit",
+			input: `{"key": "a value that is far too long"}`,
+			expectedErr: &JSONErr{
+				Msg: "String exceeds the maximum length of 16 bytes\n",
+				Pos: token.Position{
+					Column: 9,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:  "Key Exceeding Limit",
+			input: "{\"key\": 1,\n \"a key that is far too long\": 2}",
+			expectedErr: &JSONErr{
+				Msg: "String exceeds the maximum length of 16 bytes\n",
+				Pos: token.Position{
+					Column: 2,
+					Line:   2,
+				},
+			},
+		},
+		{
+			name:  "Array Element Exceeding Limit",
+			input: `["short", "an element that is far too long"]`,
+			expectedErr: &JSONErr{
+				Msg: "String exceeds the maximum length of 16 bytes\n",
+				Pos: token.Position{
+					Column: 11,
+					Line:   1,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := mylog.CreateLogger(true)
+			l := lexer.New(log, tt.input, lexer.WithMaxStringLength(16))
+			p := New(l)
+			jf, jErr := p.ParseFile()
+
+			assert.Empty(t, jf, "jsonFile should be empty")
+			assert.Equal(t, tt.expectedErr, jErr)
+		})
+	}
+}
+
+func TestJSONErrContext(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "Multiple Lines",
+			input:    "{\n  \"a\": 1,\n  \"b\" 2\n}",
+			expected: "  \"b\" 2\n      ^\n",
+		},
+		{
+			name:     "Tab Indentation",
+			input:    "{\n\t\"a\": 1,\n\t\"b\" 2\n}",
+			expected: "\t\"b\" 2\n\t    ^\n",
+		},
+		{
+			name:     "Multi-byte Characters",
+			input:    "{\"é\": tru}",
+			expected: "{\"é\": tru}\n      ^\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := mylog.CreateLogger(true)
+			l := lexer.New(log, tt.input)
+			p := New(l)
+			_, jErr := p.ParseFile()
+			require.NotNil(t, jErr, "jsonErr should not be nil")
+
+			assert.Equal(t, tt.expected, jErr.Context(tt.input))
+		})
+	}
+}
+
+func TestJSONErrContextTabWidth(t *testing.T) {
+	input := "{\n\t\"a\":\t}"
+	l := lexer.New(nil, input, lexer.WithTabWidth(4))
+	_, jErr := New(l).ParseFile()
+	require.NotNil(t, jErr, "jsonErr should not be nil")
+
+	assert.Equal(t, "\t\"a\":\t}\n\t    \t^\n", jErr.ContextTabWidth(input, 4))
+}
+
+func TestJSONErrContextOutOfRange(t *testing.T) {
+	jErr := &JSONErr{Msg: "Unexpected end of input\n", Pos: token.Position{Line: 3, Column: 1}}
+
+	assert.Empty(t, jErr.Context("{\n"))
+}
+
+func TestWithStringInterning(t *testing.T) {
+	input := `[{"id": 1, "name": "a"}, {"id": 2, "name": "id"}]`
+
+	keys := func(jf *ast.JSONFile) []*ast.StringLiteral {
+		keys := []*ast.StringLiteral{}
+		for _, el := range jf.Elements[0].(*ast.ArrayLiteral).Elements {
+			for _, k := range el.(*ast.Object).Keys {
+				keys = append(keys, k.(*ast.StringLiteral))
+			}
+		}
+		return keys
+	}
+
+	jf, jsonErr := ParseString(input, WithStringInterning())
+	require.Empty(t, jsonErr, "jsonErr should be empty")
+	interned := keys(jf)
+	require.Len(t, interned, 4)
+
+	assert.Same(t, unsafe.StringData(interned[0].Value), unsafe.StringData(interned[2].Value))
+	assert.Same(t, unsafe.StringData(interned[1].Value), unsafe.StringData(interned[3].Value))
+	assert.Equal(t, interned[0].Value, interned[0].Token.Literal)
+
+	value := jf.Elements[0].(*ast.ArrayLiteral).Elements[1].(*ast.Object).Pairs[interned[3]]
+	assert.NotSame(t, unsafe.StringData(interned[0].Value), unsafe.StringData(value.(*ast.StringLiteral).Value),
+		"values should not be interned")
+
+	plainDoc, jsonErr := ParseString(input)
+	require.Empty(t, jsonErr, "jsonErr should be empty")
+	plain := keys(plainDoc)
+	assert.NotSame(t, unsafe.StringData(plain[0].Value), unsafe.StringData(plain[2].Value))
+	assert.Equal(t, plainDoc.String(), jf.String())
+}
+
+func TestSkipPreamble(t *testing.T) {
+	comment := func(line string) bool { return strings.HasPrefix(line, "#") }
+
+	tests := []struct {
+		name        string
+		input       string
+		opts        []Option
+		expected    string
+		expectedErr *JSONErr
+	}{
+		{
+			name:     "Comment Header",
+			input:    "# exported 2024-01-01\n{\"a\":1}",
+			opts:     []Option{SkipPreamble(comment)},
+			expected: `{"a":1}`,
+		},
+		{
+			name:  "Positions Count Skipped Lines",
+			input: "# header\n# more\n{\"a\": }",
+			opts:  []Option{SkipPreamble(comment)},
+			expectedErr: &JSONErr{
+				Msg: "Missing value for key 'a'\n",
+				Pos: token.Position{
+					Column: 7,
+					Line:   3,
+				},
+			},
+		},
+		{
+			name:  "Header Without Option",
+			input: "# exported\n{\"a\":1}",
+			expectedErr: &JSONErr{
+				Msg: "Expected '{' or '[', got 'ILLEGAL' instead\n",
+				Pos: token.Position{
+					Column: 1,
+					Line:   1,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := mylog.CreateLogger(true)
+			l := lexer.New(log, tt.input)
+			p := New(l, tt.opts...)
+			jf, jsonErr := p.ParseFile()
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, jsonErr)
+				return
+			}
+			require.Empty(t, jsonErr, "jsonErr should be empty")
+
+			out, err := ast.Marshal(jf.Elements[0])
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(out))
+		})
+	}
+}
+
+func BenchmarkParseFile(b *testing.B) {
+	input := `{"key1": "value", "key2": -123, "key3": ["value", 1, true, null, -0.2e2], "key4": {"key5": null}}`
+
+	loggers := []struct {
+		name   string
+		logger *slog.Logger
+	}{
+		{name: "Text Logger To Discard", logger: slog.New(slog.NewTextHandler(io.Discard, nil))},
+		{name: "Error Level Logger", logger: mylog.CreateLogger(false)},
+		{name: "Nil Logger", logger: nil},
+	}
+
+	for _, lg := range loggers {
+		b.Run(lg.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				l := lexer.New(lg.logger, input)
+				if _, jErr := New(l).ParseFile(); jErr != nil {
+					b.Fatal(jErr.Msg)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkParseLargeObject(b *testing.B) {
+	var input strings.Builder
+	input.WriteString("{")
+	for i := 0; i < 10000; i++ {
+		if i > 0 {
+			input.WriteString(",")
+		}
+		fmt.Fprintf(&input, `"key%d": %d`, i, i)
+	}
+	input.WriteString("}")
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l := lexer.New(nil, input.String())
+		if _, jErr := New(l).ParseFile(); jErr != nil {
+			b.Fatal(jErr.Msg)
+		}
+	}
+}
+
+func BenchmarkParseRecords(b *testing.B) {
+	var input strings.Builder
+	input.WriteString("[")
+	for i := 0; i < 10000; i++ {
+		if i > 0 {
+			input.WriteString(",")
+		}
+		fmt.Fprintf(&input, `{"id": %d, "name": "user%d", "active": true, "tags": ["a", "b"]}`, i, i)
+	}
+	input.WriteString("]")
+
+	options := []struct {
+		name string
+		opts []Option
+	}{
+		{name: "Default"},
+		{name: "String Interning", opts: []Option{WithStringInterning()}},
+	}
+
+	for _, o := range options {
+		b.Run(o.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				l := lexer.New(nil, input.String())
+				if _, jErr := New(l, o.opts...).ParseFile(); jErr != nil {
+					b.Fatal(jErr.Msg)
+				}
+			}
+		})
+	}
+}
+
+func FuzzParseFile(f *testing.F) {
+	seeds := []string{
+		``,
+		`{}`,
+		`[]`,
+		`[[[]]]`,
+		`{{}}`,
+		`{"key1": "value1", "key2": -123, "key3": ["value", 1, true, null, -0.2e2]}`,
+		`{"key": {"key": null}}`,
+		`["value", 1, true, null, -0.2e2, {"key": 123}]`,
+		`{"key1": "value1", "key2": "value2", "key1": "value3"}`,
+		`{"key1": - }`,
+		`{"key1": -.95}`,
+		`{"key1": -100e}`,
+		`{key1: 0}`,
+		`["value1", ]`,
+		`{"key": "value1"} "misplaced quoted value"`,
+		"{\"\\\"\\\"key\\u00Fa\\b\\\"\": [\"value\\n\\t\\f\"]}",
+		"{\"key\\u00FZ\": 1}",
+		"{\"key\": ['value']}",
+		"[\"value]",
+		`"`,
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		log := mylog.CreateLogger(false)
+		l := lexer.New(log, input)
+		p := New(l)
+		jf, jsonErr := p.ParseFile()
+
+		if jsonErr != nil {
+			assert.Nil(t, jf, "jsonFile should be empty when jsonErr is set")
+			assert.NotEmpty(t, jsonErr.Msg, "jsonErr.Msg should not be empty")
+			return
+		}
+
+		assert.NotPanics(t, func() { jf.ToInterface() }, "ToInterface() should not panic")
+		assert.NotEmpty(t, jf.String(), "jsonFile.String() should not be empty")
+	})
+}
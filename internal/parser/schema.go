@@ -0,0 +1,354 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/nobletk/json-parser/internal/ast"
+	"github.com/nobletk/json-parser/internal/token"
+)
+
+// SchemaError points at a part of a JSON Schema that does not conform to
+// the meta-schema, see ValidateSchemaDocument. Pointer is the JSON Pointer
+// of the offending value, e.g. "/properties/name/type".
+type SchemaError struct {
+	Pointer string
+	Msg     string
+	Pos     token.Position
+}
+
+// schemaKind is the type of value a schema keyword takes.
+type schemaKind int
+
+const (
+	kindAny schemaKind = iota
+	kindSchema
+	kindSchemaOrSchemas
+	kindSchemaList
+	kindSchemaMap
+	kindType
+	kindString
+	kindStringList
+	kindStringListMap
+	kindDependencies
+	kindBoolean
+	kindBooleanMap
+	kindNumber
+	kindNumberOrBoolean
+	kindPositiveNumber
+	kindCount
+	kindArray
+)
+
+// schemaKeywords lists the keywords of the JSON Schema drafts 4 to 2020-12
+// understood by ValidateSchemaDocument and the values they take. Keywords
+// whose value changed between drafts accept every form, e.g. the draft 4
+// boolean "exclusiveMinimum" as well as the later number. Patterns are only
+// checked to be strings: they use ECMA-262 syntax, which Go's regexp does
+// not fully support, e.g. lookaheads such as (?!foo).
+var schemaKeywords = map[string]schemaKind{
+	"$schema":               kindString,
+	"$id":                   kindString,
+	"id":                    kindString,
+	"$ref":                  kindString,
+	"$anchor":               kindString,
+	"$dynamicRef":           kindString,
+	"$dynamicAnchor":        kindString,
+	"$recursiveRef":         kindString,
+	"$recursiveAnchor":      kindBoolean,
+	"$vocabulary":           kindBooleanMap,
+	"$comment":              kindString,
+	"$defs":                 kindSchemaMap,
+	"definitions":           kindSchemaMap,
+	"title":                 kindString,
+	"description":           kindString,
+	"format":                kindString,
+	"contentEncoding":       kindString,
+	"contentMediaType":      kindString,
+	"contentSchema":         kindSchema,
+	"default":               kindAny,
+	"const":                 kindAny,
+	"examples":              kindArray,
+	"enum":                  kindArray,
+	"type":                  kindType,
+	"readOnly":              kindBoolean,
+	"writeOnly":             kindBoolean,
+	"deprecated":            kindBoolean,
+	"allOf":                 kindSchemaList,
+	"anyOf":                 kindSchemaList,
+	"oneOf":                 kindSchemaList,
+	"not":                   kindSchema,
+	"if":                    kindSchema,
+	"then":                  kindSchema,
+	"else":                  kindSchema,
+	"properties":            kindSchemaMap,
+	"patternProperties":     kindSchemaMap,
+	"additionalProperties":  kindSchema,
+	"unevaluatedProperties": kindSchema,
+	"propertyNames":         kindSchema,
+	"dependentSchemas":      kindSchemaMap,
+	"dependentRequired":     kindStringListMap,
+	"dependencies":          kindDependencies,
+	"required":              kindStringList,
+	"minProperties":         kindCount,
+	"maxProperties":         kindCount,
+	"items":                 kindSchemaOrSchemas,
+	"prefixItems":           kindSchemaList,
+	"additionalItems":       kindSchema,
+	"unevaluatedItems":      kindSchema,
+	"contains":              kindSchema,
+	"minContains":           kindCount,
+	"maxContains":           kindCount,
+	"minItems":              kindCount,
+	"maxItems":              kindCount,
+	"uniqueItems":           kindBoolean,
+	"minLength":             kindCount,
+	"maxLength":             kindCount,
+	"pattern":               kindString,
+	"multipleOf":            kindPositiveNumber,
+	"minimum":               kindNumber,
+	"maximum":               kindNumber,
+	"exclusiveMinimum":      kindNumberOrBoolean,
+	"exclusiveMaximum":      kindNumberOrBoolean,
+}
+
+var schemaTypes = map[string]bool{
+	"null":    true,
+	"boolean": true,
+	"object":  true,
+	"array":   true,
+	"number":  true,
+	"integer": true,
+	"string":  true,
+}
+
+// ValidateSchemaDocument checks that doc is itself a valid JSON Schema
+// before it is used to validate other documents: every schema must be an
+// object or a boolean, only known keywords may be used and each keyword
+// must have a value of the right type, e.g. "type" a type name or a list of
+// them and "properties" an object of schemas. It returns the problems found
+// in document order, or nil when there are none.
+func ValidateSchemaDocument(doc ast.Element) []SchemaError {
+	var errs []SchemaError
+	validateSchema(ast.Thaw(doc), "", &errs)
+	return errs
+}
+
+func validateSchema(e ast.Element, pointer string, errs *[]SchemaError) {
+	switch e := e.(type) {
+	case *ast.Boolean:
+		return
+	case *ast.Object:
+		e.Each(func(key string, value ast.Element) bool {
+			value = ast.Thaw(value)
+			key = ast.UnescapeKey(key)
+			keyPointer := pointer + "/" + ast.EscapePointer(key)
+
+			kind, ok := schemaKeywords[key]
+			if !ok {
+				addSchemaError(errs, keyPointer, value, "Unknown schema keyword '%s'\n", key)
+				return true
+			}
+			validateKeyword(key, kind, value, keyPointer, errs)
+			return true
+		})
+	default:
+		addSchemaError(errs, pointer, e, "Expected a schema object or boolean, got %s\n", ast.TypeName(e))
+	}
+}
+
+func validateKeyword(key string, kind schemaKind, value ast.Element, pointer string, errs *[]SchemaError) {
+	switch kind {
+	case kindAny:
+	case kindSchema:
+		validateSchema(value, pointer, errs)
+	case kindSchemaOrSchemas:
+		if _, ok := value.(*ast.ArrayLiteral); ok {
+			validateKeyword(key, kindSchemaList, value, pointer, errs)
+			return
+		}
+		validateSchema(value, pointer, errs)
+	case kindSchemaList:
+		arr, ok := value.(*ast.ArrayLiteral)
+		if !ok || len(arr.Elements) == 0 {
+			addSchemaError(errs, pointer, value, "Keyword '%s' must be a non-empty array of schemas\n", key)
+			return
+		}
+		for i, el := range arr.Elements {
+			validateSchema(ast.Thaw(el), fmt.Sprintf("%s/%d", pointer, i), errs)
+		}
+	case kindSchemaMap:
+		obj, ok := value.(*ast.Object)
+		if !ok {
+			addSchemaError(errs, pointer, value, "Keyword '%s' must be an object of schemas\n", key)
+			return
+		}
+		obj.Each(func(name string, schema ast.Element) bool {
+			validateSchema(ast.Thaw(schema), memberPointer(pointer, name), errs)
+			return true
+		})
+	case kindType:
+		validateType(value, pointer, errs)
+	case kindString:
+		if _, ok := value.(*ast.StringLiteral); !ok {
+			addSchemaError(errs, pointer, value, "Keyword '%s' must be a string, got %s\n", key, ast.TypeName(value))
+		}
+	case kindStringList:
+		validateStringList(key, value, pointer, errs)
+	case kindStringListMap:
+		obj, ok := value.(*ast.Object)
+		if !ok {
+			addSchemaError(errs, pointer, value, "Keyword '%s' must be an object of string arrays\n", key)
+			return
+		}
+		obj.Each(func(name string, list ast.Element) bool {
+			validateStringList(key, ast.Thaw(list), memberPointer(pointer, name), errs)
+			return true
+		})
+	case kindDependencies:
+		obj, ok := value.(*ast.Object)
+		if !ok {
+			addSchemaError(errs, pointer, value, "Keyword '%s' must be an object of schemas or string arrays\n", key)
+			return
+		}
+		obj.Each(func(name string, dep ast.Element) bool {
+			dep = ast.Thaw(dep)
+			namePointer := memberPointer(pointer, name)
+			if _, ok := dep.(*ast.ArrayLiteral); ok {
+				validateStringList(key, dep, namePointer, errs)
+			} else {
+				validateSchema(dep, namePointer, errs)
+			}
+			return true
+		})
+	case kindBoolean:
+		if _, ok := value.(*ast.Boolean); !ok {
+			addSchemaError(errs, pointer, value, "Keyword '%s' must be a boolean, got %s\n", key, ast.TypeName(value))
+		}
+	case kindBooleanMap:
+		obj, ok := value.(*ast.Object)
+		if !ok {
+			addSchemaError(errs, pointer, value, "Keyword '%s' must be an object of booleans\n", key)
+			return
+		}
+		obj.Each(func(name string, b ast.Element) bool {
+			if _, ok := ast.Thaw(b).(*ast.Boolean); !ok {
+				addSchemaError(errs, memberPointer(pointer, name), b, "Keyword '%s' must be an object of booleans\n",
+					key)
+			}
+			return true
+		})
+	case kindNumber:
+		if _, ok := value.(*ast.NumberLiteral); !ok {
+			addSchemaError(errs, pointer, value, "Keyword '%s' must be a number, got %s\n", key, ast.TypeName(value))
+		}
+	case kindNumberOrBoolean:
+		switch value.(type) {
+		case *ast.NumberLiteral, *ast.Boolean:
+		default:
+			addSchemaError(errs, pointer, value, "Keyword '%s' must be a number or a boolean, got %s\n", key,
+				ast.TypeName(value))
+		}
+	case kindPositiveNumber:
+		nl, ok := value.(*ast.NumberLiteral)
+		if !ok || nl.Value <= 0 {
+			addSchemaError(errs, pointer, value, "Keyword '%s' must be a number greater than 0\n", key)
+		}
+	case kindCount:
+		nl, ok := value.(*ast.NumberLiteral)
+		if !ok || !nl.IsInteger() || nl.Value < 0 {
+			addSchemaError(errs, pointer, value, "Keyword '%s' must be a non-negative integer\n", key)
+		}
+	case kindArray:
+		if _, ok := value.(*ast.ArrayLiteral); !ok {
+			addSchemaError(errs, pointer, value, "Keyword '%s' must be an array, got %s\n", key, ast.TypeName(value))
+		}
+	}
+}
+
+// validateType checks the value of "type", which is a type name or a
+// non-empty array of distinct type names.
+func validateType(value ast.Element, pointer string, errs *[]SchemaError) {
+	checkName := func(e ast.Element, pointer string) string {
+		sl, ok := e.(*ast.StringLiteral)
+		if !ok {
+			addSchemaError(errs, pointer, e, "Keyword 'type' must be a type name or an array of them, got %s\n",
+				ast.TypeName(e))
+			return ""
+		}
+		name := stringValue(sl)
+		if !schemaTypes[name] {
+			addSchemaError(errs, pointer, e, "Unknown type '%s'\n", name)
+		}
+		return name
+	}
+
+	arr, ok := value.(*ast.ArrayLiteral)
+	if !ok {
+		checkName(value, pointer)
+		return
+	}
+	if len(arr.Elements) == 0 {
+		addSchemaError(errs, pointer, value, "Keyword 'type' must not be an empty array\n")
+		return
+	}
+
+	seen := make(map[string]bool)
+	for i, el := range arr.Elements {
+		elPointer := fmt.Sprintf("%s/%d", pointer, i)
+		name := checkName(ast.Thaw(el), elPointer)
+		if name != "" && seen[name] {
+			addSchemaError(errs, elPointer, el, "Duplicate type '%s'\n", name)
+		}
+		seen[name] = true
+	}
+}
+
+// validateStringList checks a value like that of "required", an array of
+// distinct strings.
+func validateStringList(key string, value ast.Element, pointer string, errs *[]SchemaError) {
+	arr, ok := value.(*ast.ArrayLiteral)
+	if !ok {
+		addSchemaError(errs, pointer, value, "Keyword '%s' must be an array of strings, got %s\n", key,
+			ast.TypeName(value))
+		return
+	}
+
+	seen := make(map[string]bool)
+	for i, el := range arr.Elements {
+		elPointer := fmt.Sprintf("%s/%d", pointer, i)
+		sl, ok := ast.Thaw(el).(*ast.StringLiteral)
+		if !ok {
+			addSchemaError(errs, elPointer, el, "Keyword '%s' must be an array of strings, got %s\n", key,
+				ast.TypeName(el))
+			continue
+		}
+		str := stringValue(sl)
+		if seen[str] {
+			addSchemaError(errs, elPointer, el, "Duplicate '%s' in '%s'\n", str, key)
+		}
+		seen[str] = true
+	}
+}
+
+func addSchemaError(errs *[]SchemaError, pointer string, e ast.Element, format string, a ...any) {
+	*errs = append(*errs, SchemaError{
+		Pointer: pointer,
+		Msg:     fmt.Sprintf(format, a...),
+		Pos:     elementPosition(ast.Thaw(e)),
+	})
+}
+
+// memberPointer returns the JSON Pointer of the member key, as passed by
+// Object.Each, of the object at pointer.
+func memberPointer(pointer, key string) string {
+	return pointer + "/" + ast.EscapePointer(ast.UnescapeKey(key))
+}
+
+// stringValue returns the decoded text of sl, or its raw value when it
+// holds an invalid escape.
+func stringValue(sl *ast.StringLiteral) string {
+	if str, err := sl.Unescaped(); err == nil {
+		return str
+	}
+	return sl.Value
+}
@@ -0,0 +1,187 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/nobletk/json-parser/internal/token"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSchemaDocument(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []SchemaError
+	}{
+		{
+			name: "Valid Schema",
+			input: `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "properties": {
+    "name": {"type": "string", "minLength": 1, "pattern": "^[a-z]+$"},
+    "tags": {"type": "array", "items": {"type": "string"}, "uniqueItems": true},
+    "age": {"type": ["integer", "null"], "minimum": 0}
+  },
+  "required": ["name"],
+  "additionalProperties": false
+}`,
+		},
+		{
+			name:  "Type As A Number",
+			input: `{"type": 5}`,
+			expected: []SchemaError{
+				{
+					Pointer: "/type",
+					Msg:     "Keyword 'type' must be a type name or an array of them, got number\n",
+					Pos:     token.Position{Line: 1, Column: 10},
+				},
+			},
+		},
+		{
+			name:  "Unknown Type Name",
+			input: `{"type": ["string", "text", "string"]}`,
+			expected: []SchemaError{
+				{Pointer: "/type/1", Msg: "Unknown type 'text'\n", Pos: token.Position{Line: 1, Column: 21}},
+				{Pointer: "/type/2", Msg: "Duplicate type 'string'\n", Pos: token.Position{Line: 1, Column: 29}},
+			},
+		},
+		{
+			name:  "Properties Not An Object",
+			input: `{"properties": ["name"]}`,
+			expected: []SchemaError{
+				{
+					Pointer: "/properties",
+					Msg:     "Keyword 'properties' must be an object of schemas\n",
+					Pos:     token.Position{Line: 1, Column: 16},
+				},
+			},
+		},
+		{
+			name: "Nested Problems",
+			input: `{
+  "properties": {
+    "a/b": {"type": "string", "maxLength": -1},
+    "c": 3
+  },
+  "required": "a/b",
+  "unknown": true
+}`,
+			expected: []SchemaError{
+				{
+					Pointer: "/properties/a~1b/maxLength",
+					Msg:     "Keyword 'maxLength' must be a non-negative integer\n",
+					Pos:     token.Position{Line: 3, Column: 44},
+				},
+				{
+					Pointer: "/properties/c",
+					Msg:     "Expected a schema object or boolean, got number\n",
+					Pos:     token.Position{Line: 4, Column: 10},
+				},
+				{
+					Pointer: "/required",
+					Msg:     "Keyword 'required' must be an array of strings, got string\n",
+					Pos:     token.Position{Line: 6, Column: 15},
+				},
+				{
+					Pointer: "/unknown",
+					Msg:     "Unknown schema keyword 'unknown'\n",
+					Pos:     token.Position{Line: 7, Column: 14},
+				},
+			},
+		},
+		{
+			name:  "Empty Schema List",
+			input: `{"anyOf": []}`,
+			expected: []SchemaError{
+				{
+					Pointer: "/anyOf",
+					Msg:     "Keyword 'anyOf' must be a non-empty array of schemas\n",
+					Pos:     token.Position{Line: 1, Column: 11},
+				},
+			},
+		},
+		{
+			name:  "ECMA-262 Patterns",
+			input: `{"pattern": "^(?!foo)\\w+$", "patternProperties": {"^(a)\\1$": {}}}`,
+		},
+		{
+			name: "Draft 4 And 2019-09 Keywords",
+			input: `{
+  "$schema": "http://json-schema.org/draft-04/schema#",
+  "$vocabulary": {"https://json-schema.org/draft/2019-09/vocab/core": true},
+  "$recursiveAnchor": true,
+  "minimum": 0,
+  "exclusiveMinimum": true,
+  "maximum": 10,
+  "exclusiveMaximum": 10,
+  "dependencies": {"a": ["b"], "c": {"$recursiveRef": "#"}},
+  "contentMediaType": "application/json",
+  "contentSchema": {"type": "object"}
+}`,
+		},
+		{
+			name:  "Invalid Draft 4 And 2019-09 Keywords",
+			input: `{"exclusiveMinimum": "yes", "dependencies": {"a": [1], "b": 2}, "$vocabulary": {"v": 1}}`,
+			expected: []SchemaError{
+				{
+					Pointer: "/exclusiveMinimum",
+					Msg:     "Keyword 'exclusiveMinimum' must be a number or a boolean, got string\n",
+					Pos:     token.Position{Line: 1, Column: 22},
+				},
+				{
+					Pointer: "/dependencies/a/0",
+					Msg:     "Keyword 'dependencies' must be an array of strings, got number\n",
+					Pos:     token.Position{Line: 1, Column: 52},
+				},
+				{
+					Pointer: "/dependencies/b",
+					Msg:     "Expected a schema object or boolean, got number\n",
+					Pos:     token.Position{Line: 1, Column: 61},
+				},
+				{
+					Pointer: "/$vocabulary/v",
+					Msg:     "Keyword '$vocabulary' must be an object of booleans\n",
+					Pos:     token.Position{Line: 1, Column: 86},
+				},
+			},
+		},
+		{
+			name:  "Escaped Keys And Values",
+			input: `{"properties": {"a\u002Fb": 3, "c": {"type": "str\u0069ng"}}, "required": ["a", "\u0061"]}`,
+			expected: []SchemaError{
+				{
+					Pointer: "/properties/a~1b",
+					Msg:     "Expected a schema object or boolean, got number\n",
+					Pos:     token.Position{Line: 1, Column: 29},
+				},
+				{
+					Pointer: "/required/1",
+					Msg:     "Duplicate 'a' in 'required'\n",
+					Pos:     token.Position{Line: 1, Column: 81},
+				},
+			},
+		},
+		{
+			name:  "Root Not A Schema",
+			input: `[{"type": "string"}]`,
+			expected: []SchemaError{
+				{
+					Pointer: "",
+					Msg:     "Expected a schema object or boolean, got array\n",
+					Pos:     token.Position{Line: 1, Column: 1},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jf, jErr := ParseString(tt.input)
+			require.Nil(t, jErr)
+
+			assert.Equal(t, tt.expected, ValidateSchemaDocument(jf.Elements[0]))
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package parser
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/nobletk/json-parser/internal/ast"
+	"github.com/nobletk/json-parser/internal/lexer"
+	"github.com/nobletk/json-parser/internal/token"
+)
+
+// RecordSeparator is the ASCII RS character that frames the values of RFC
+// 7464 JSON text sequences.
+const RecordSeparator byte = 0x1E
+
+// ParseSequence parses the values in r separated by sep, e.g. by
+// RecordSeparator for JSON text sequences, and returns one JSONFile per
+// value. Records holding only whitespace are skipped. Errors point at the
+// position in r, and parsing stops at the first invalid record.
+func ParseSequence(r io.Reader, sep byte, opts ...Option) ([]*ast.JSONFile, *JSONErr) {
+	br := bufio.NewReader(r)
+	docs := []*ast.JSONFile{}
+	pos := token.Position{Line: 1, Column: 1}
+	var p *Parser
+
+	for {
+		text, err := br.ReadString(sep)
+		if err != nil && !errors.Is(err, io.EOF) {
+			msg := fmt.Sprintf("Failed to read record: %s\n", err)
+			return nil, &JSONErr{Msg: msg, Pos: pos}
+		}
+
+		record := strings.TrimSuffix(text, string(sep))
+		if strings.TrimSpace(record) != "" {
+			l := lexer.NewAt(nil, record, pos)
+			if p == nil {
+				p = New(l, opts...)
+			} else {
+				p.Reset(l)
+			}
+
+			doc, jErr := p.ParseFile()
+			if jErr != nil {
+				return nil, jErr
+			}
+			docs = append(docs, doc)
+		}
+
+		if err != nil {
+			return docs, nil
+		}
+		pos = advance(pos, text)
+	}
+}
+
+// advance returns the position just past text when it starts at pos.
+func advance(pos token.Position, text string) token.Position {
+	if i := strings.LastIndexByte(text, '\n'); i >= 0 {
+		return token.Position{Line: pos.Line + strings.Count(text, "\n"), Column: len(text) - i}
+	}
+	return token.Position{Line: pos.Line, Column: pos.Column + len(text)}
+}
@@ -0,0 +1,81 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nobletk/json-parser/internal/token"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSequence(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		sep         byte
+		expected    []string
+		expectedErr *JSONErr
+	}{
+		{
+			name:     "JSON Text Sequence",
+			input:    "\x1e{\"id\": 1}\n\x1e[2, 3]\n",
+			sep:      RecordSeparator,
+			expected: []string{`{"id":1}`, `[2, 3]`},
+		},
+		{
+			name:     "Custom Separator And Empty Records",
+			input:    "{\"a\": true}||  ||\n[null]|",
+			sep:      '|',
+			expected: []string{`{"a":true}`, `[null]`},
+		},
+		{
+			name:     "Empty Input",
+			input:    "",
+			sep:      RecordSeparator,
+			expected: []string{},
+		},
+		{
+			name:  "Invalid Record Position",
+			input: "\x1e{\"id\": 1}\n\x1e{\n  \"id\": }\n",
+			sep:   RecordSeparator,
+			expectedErr: &JSONErr{
+				Msg: "Missing value for key 'id'\n",
+				Pos: token.Position{
+					Column: 9,
+					Line:   3,
+				},
+			},
+		},
+		{
+			name:  "Two Values In One Record",
+			input: "\x1e[1]\n\x1e[2] [3]\n",
+			sep:   RecordSeparator,
+			expectedErr: &JSONErr{
+				Msg: "Unexpected trailing content after JSON value\n",
+				Pos: token.Position{
+					Column: 6,
+					Line:   2,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docs, jErr := ParseSequence(strings.NewReader(tt.input), tt.sep)
+			if tt.expectedErr != nil {
+				assert.Nil(t, docs)
+				assert.Equal(t, tt.expectedErr, jErr)
+				return
+			}
+			require.Nil(t, jErr, "jsonErr should be nil")
+
+			out := []string{}
+			for _, doc := range docs {
+				out = append(out, doc.String())
+			}
+			assert.Equal(t, tt.expected, out)
+		})
+	}
+}
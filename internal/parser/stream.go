@@ -0,0 +1,111 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/nobletk/json-parser/internal/ast"
+	"github.com/nobletk/json-parser/internal/lexer"
+	"github.com/nobletk/json-parser/internal/token"
+)
+
+// ArrayStream parses the elements of a top-level array one at a time, so
+// the array as a whole is never held in memory.
+type ArrayStream struct {
+	p *Parser
+
+	start   token.Position
+	started bool
+	done    bool
+	count   int
+	err     *JSONErr
+}
+
+func NewArrayStream(l *lexer.Lexer, opts ...Option) *ArrayStream {
+	return &ArrayStream{p: New(l, opts...)}
+}
+
+// Next parses the next element of the array. It returns false once the
+// closing bracket has been consumed or an error was found; an error is
+// returned again on every following call.
+func (as *ArrayStream) Next() (ast.Element, bool, *JSONErr) {
+	if as.err != nil || as.done {
+		return nil, false, as.err
+	}
+
+	elem, err := as.next()
+	if err != nil {
+		if as.p.logging() {
+			as.p.logger.Info("Array Stream Stopped:", "jsonErr", err)
+		}
+		as.err = err
+		return nil, false, err
+	}
+	if elem == nil {
+		as.done = true
+		return nil, false, nil
+	}
+
+	as.count++
+	return elem, true, nil
+}
+
+func (as *ArrayStream) next() (ast.Element, *JSONErr) {
+	p := as.p
+
+	if !as.started {
+		as.started = true
+		as.start = p.curToken.Position
+
+		if !p.curTokenIs(token.LBRACKET) {
+			msg := fmt.Sprintf("Expected '[', got '%v' instead\n", p.curToken.Type)
+			return nil, &JSONErr{Msg: msg, Pos: p.curToken.Position}
+		}
+
+		if err := p.enterNested(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.unexpectedEOFError("array", as.start); err != nil {
+		return nil, err
+	}
+
+	if p.peekTokenIs(token.RBRACKET) {
+		p.nextToken()
+		return nil, as.end()
+	}
+
+	if as.count > 0 {
+		if !p.peekTokenIs(token.COMMA) {
+			msg := fmt.Sprintf("Expected ',', ']'. got '%v' instead\n", p.peekToken.Type)
+			return nil, &JSONErr{Msg: msg, Pos: p.peekToken.Position}
+		}
+		p.nextToken()
+
+		if err := p.unexpectedEOFError("array", as.start); err != nil {
+			return nil, err
+		}
+
+		if p.peekTokenIs(token.RBRACKET) {
+			return nil, p.trailingCommaError(p.peekToken)
+		}
+	}
+
+	p.nextToken()
+	return p.parseValue()
+}
+
+func (as *ArrayStream) end() *JSONErr {
+	p := as.p
+	p.leaveNested()
+	p.nextToken()
+
+	if err := p.trailingContentError(); err != nil {
+		return err
+	}
+
+	if p.logging() {
+		p.logger.Info("Array Stream Completed:", "count", as.count)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/nobletk/json-parser/internal/lexer"
+	"github.com/nobletk/json-parser/internal/token"
+	"github.com/nobletk/json-parser/pkg/mylog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArrayStream(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    []interface{}
+		expectedErr *JSONErr
+	}{
+		{
+			name:     "Array Of Integers",
+			input:    `[1,2,3]`,
+			expected: []interface{}{float64(1), float64(2), float64(3)},
+		},
+		{
+			name:     "Empty Array",
+			input:    ` [ ] `,
+			expected: []interface{}{},
+		},
+		{
+			name:  "Array Of Mixed Values",
+			input: "[\n  {\"key\": [true]},\n  \"value\",\n  null\n]\n",
+			expected: []interface{}{
+				map[string]interface{}{"key": []interface{}{true}},
+				"value",
+				nil,
+			},
+		},
+		{
+			name:     "Root Is Not An Array",
+			input:    `{"key": 1}`,
+			expected: []interface{}{},
+			expectedErr: &JSONErr{
+				Msg: "Expected '[', got '{' instead\n",
+				Pos: token.Position{
+					Column: 1,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:     "Missing Comma",
+			input:    `[1 2]`,
+			expected: []interface{}{float64(1)},
+			expectedErr: &JSONErr{
+				Msg: "Expected ',', ']'. got 'NUMBER' instead\n",
+				Pos: token.Position{
+					Column: 4,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:     "Trailing Comma",
+			input:    `[1, 2, ]`,
+			expected: []interface{}{float64(1), float64(2)},
+			expectedErr: &JSONErr{
+				Msg: "Trailing commas are not allowed in JSON; remove the ',' before ']'\n",
+				Pos: token.Position{
+					Column: 8,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:     "Unterminated Array",
+			input:    `[1, 2`,
+			expected: []interface{}{float64(1), float64(2)},
+			expectedErr: &JSONErr{
+				Msg: "Unexpected end of input while parsing array\n",
+				Pos: token.Position{
+					Column: 1,
+					Line:   1,
+				},
+			},
+		},
+		{
+			name:     "Content After Array",
+			input:    `[1] 2`,
+			expected: []interface{}{float64(1)},
+			expectedErr: &JSONErr{
+				Msg: "Unexpected trailing content after JSON value\n",
+				Pos: token.Position{
+					Column: 5,
+					Line:   1,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := mylog.CreateLogger(true)
+			l := lexer.New(log, tt.input)
+			as := NewArrayStream(l)
+
+			actual := []interface{}{}
+			for {
+				elem, ok, jErr := as.Next()
+				if !ok {
+					assert.Equal(t, tt.expectedErr, jErr)
+					break
+				}
+				require.Empty(t, jErr, "jsonErr should be empty")
+				actual = append(actual, elem.ToInterface())
+			}
+			assert.Equal(t, tt.expected, actual)
+
+			elem, ok, jErr := as.Next()
+			assert.Nil(t, elem, "elem should be nil once the stream is done")
+			assert.False(t, ok, "ok should be false once the stream is done")
+			assert.Equal(t, tt.expectedErr, jErr)
+		})
+	}
+}
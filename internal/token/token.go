@@ -11,6 +11,12 @@ const (
 	FALSE = "FALSE"
 	NULL  = "NULL"
 
+	// UNDEFINED is the JavaScript `undefined` keyword, only accepted by
+	// parsers created with parser.AllowUndefined.
+	UNDEFINED = "UNDEFINED"
+
+	COMMENT = "COMMENT"
+
 	COMMA = ","
 	COLON = ":"
 
@@ -31,12 +37,19 @@ type Token struct {
 	Type     TokenType
 	Literal  string
 	Position Position
+	// Reason explains why an ILLEGAL token was rejected, when known.
+	Reason string
+	// RawLiteral is the exact source of a STRING token, quotes and escapes
+	// included, when the lexer is created with lexer.WithRawStrings.
+	RawLiteral string
 }
 
 var keywords = map[string]TokenType{
 	"true":  TRUE,
 	"false": FALSE,
 	"null":  NULL,
+
+	"undefined": UNDEFINED,
 }
 
 func LookupIdent(ident string) TokenType {
@@ -2,6 +2,7 @@ package mylog
 
 import (
 	"log/slog"
+	"unicode/utf8"
 
 	prettylog "github.com/nobletk/json-parser/pkg/pretty-log"
 )
@@ -11,7 +12,27 @@ type MyLog struct {
 	Logger *slog.Logger
 }
 
-func CreateLogger(debug bool) *slog.Logger {
+type Option func(*config)
+
+type config struct {
+	maxValueLen int
+}
+
+// WithMaxLogValueLen logs string values longer than n bytes as their first
+// n bytes followed by "… (truncated)", so huge literals do not flood debug
+// output. A limit of zero or less disables truncation.
+func WithMaxLogValueLen(n int) Option {
+	return func(c *config) {
+		c.maxValueLen = n
+	}
+}
+
+func CreateLogger(debug bool, opts ...Option) *slog.Logger {
+	var cfg config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
 	var level slog.Level
 
 	if debug {
@@ -20,19 +41,33 @@ func CreateLogger(debug bool) *slog.Logger {
 		level = slog.LevelError
 	}
 
-	opts := &slog.HandlerOptions{
+	handlerOpts := &slog.HandlerOptions{
 		Level:     level,
 		AddSource: false,
 		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
 			if a.Key == "nothing" {
 				return slog.Attr{}
 			}
+			if cfg.maxValueLen > 0 && a.Value.Kind() == slog.KindString {
+				return slog.String(a.Key, truncate(a.Value.String(), cfg.maxValueLen))
+			}
 			return a
 		},
 	}
-	logger := slog.New(prettylog.NewHandler(opts))
+	logger := slog.New(prettylog.NewHandler(handlerOpts))
 
 	logger = logger.WithGroup("data")
 
 	return logger
 }
+
+// truncate shortens s to at most n bytes without splitting a character.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + "… (truncated)"
+}
@@ -0,0 +1,66 @@
+package mylog
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// captureStdout returns what fn prints, since the pretty handler always
+// writes to os.Stdout.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	fn()
+	require.NoError(t, w.Close())
+
+	var out bytes.Buffer
+	_, err = io.Copy(&out, r)
+	require.NoError(t, err)
+	return out.String()
+}
+
+func TestWithMaxLogValueLen(t *testing.T) {
+	literal := strings.Repeat("x", 1<<20)
+
+	out := captureStdout(t, func() {
+		logger := CreateLogger(true, WithMaxLogValueLen(8))
+		logger.Info("Reading String:", "literal", literal, "short", "abc", "pos", 42)
+	})
+
+	assert.Contains(t, out, `"literal": "xxxxxxxx… (truncated)"`)
+	assert.Contains(t, out, `"short": "abc"`)
+	assert.Contains(t, out, `"pos": 42`)
+	assert.Less(t, len(out), 1000)
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		n        int
+		expected string
+	}{
+		{name: "Short", input: "abc", n: 3, expected: "abc"},
+		{name: "Long", input: "abcdef", n: 3, expected: "abc… (truncated)"},
+		{name: "Multi-byte Character", input: "aéb", n: 2, expected: "a… (truncated)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, truncate(tt.input, tt.n))
+		})
+	}
+}